@@ -1,3 +1,7 @@
+// Inches added to a curtain's height for hems and headers. It is saved with
+// each curtain so the server prices older measurements with their own allowance.
+const CURTAIN_HEM_ALLOWANCE = 15;
+
 // Rod calculation helper function
 const calculateRods = (widths, rodLength = 144) => {
   // Sort widths from largest to smallest
@@ -176,8 +180,9 @@ export const INTERIOR_SCHEMAS = {
       // Round pieces to get parts
       const parts = pieces;
 
-      // Calculate Main Metre: ((Height+12)*Part)/39
-      const mainMetre = ((height + 12) * Math.ceil(parts)) / 39; // Calculate costs
+      // Calculate Main Metre: ((Height+Hem)*Part)/39
+      const mainMetre =
+        ((height + CURTAIN_HEM_ALLOWANCE) * Math.ceil(parts)) / 39; // Calculate costs
       const clothRatePerMeter = parseFloat(data.clothRatePerMeter) || 0;
       const stitchingCostPerPart = parseFloat(data.stitchingCostPerPart) || 0;
 
@@ -224,6 +229,7 @@ export const INTERIOR_SCHEMAS = {
         opening,
 
         // Curtain calculations
+        hemAllowance: CURTAIN_HEM_ALLOWANCE,
         mainMetre,
         clothCost,
         stitchingCost,
@@ -0,0 +1,67 @@
+// Worker session tokens. Access tokens are short-lived; the refresh token from
+// login or the last refresh is exchanged for a new pair when the API answers 401.
+import AsyncStorage from "@react-native-async-storage/async-storage";
+
+const REFRESH_URL = "https://interior-app-production.up.railway.app/api/auth/refresh";
+
+export async function saveTokens(data) {
+  await AsyncStorage.multiSet([
+    ["token", data.token],
+    ["refreshToken", data.refreshToken],
+  ]);
+}
+
+export async function clearTokens() {
+  await AsyncStorage.multiRemove(["token", "refreshToken"]);
+}
+
+// Fetches an API URL with the access token, refreshing it and retrying once
+// if it has expired. A 401 that survives the retry means the worker has to
+// log in again.
+export async function authFetch(url, options = {}) {
+  const send = async () => {
+    const token = await AsyncStorage.getItem("token");
+    return fetch(url, {
+      ...options,
+      headers: { ...options.headers, Authorization: `Bearer ${token}` },
+    });
+  };
+  const response = await send();
+  if (response.status !== 401 || !(await refreshTokens())) {
+    return response;
+  }
+  return send();
+}
+
+let pendingRefresh = null;
+
+// Each refresh token works only once, so concurrent requests share one refresh
+function refreshTokens() {
+  if (!pendingRefresh) {
+    pendingRefresh = requestRefresh().finally(() => {
+      pendingRefresh = null;
+    });
+  }
+  return pendingRefresh;
+}
+
+async function requestRefresh() {
+  const refreshToken = await AsyncStorage.getItem("refreshToken");
+  if (!refreshToken) {
+    return false;
+  }
+  try {
+    const response = await fetch(REFRESH_URL, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ refreshToken }),
+    });
+    if (!response.ok) {
+      return false;
+    }
+    await saveTokens(await response.json());
+    return true;
+  } catch (_error) {
+    return false;
+  }
+}
@@ -17,6 +17,7 @@ import {
 import { LinearGradient } from 'expo-linear-gradient';
 import { COLORS } from "./styles/colors";
 import { calculateProjectTotals } from "./components/projectTotals";
+import { authFetch, clearTokens } from "./components/session";
 
 const { width, height } = Dimensions.get('window');
 
@@ -160,7 +161,15 @@ export default function Home() {
 
   // Logout handler
   const handleLogout = async () => {
-    await AsyncStorage.removeItem("token");
+    try {
+      // End the session on the server so its refresh token stops working
+      await authFetch("https://interior-app-production.up.railway.app/api/worker/logout", {
+        method: "POST",
+      });
+    } catch (_error) {
+      // Logging out locally is enough when offline
+    }
+    await clearTokens();
     router.replace("/login");
   };
 
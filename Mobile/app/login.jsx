@@ -5,6 +5,7 @@ import { router } from 'expo-router';
 import { COLORS } from './styles/colors';
 import { LinearGradient } from 'expo-linear-gradient';
 import { Ionicons } from '@expo/vector-icons';
+import { saveTokens } from './components/session';
 
 const { width, height } = Dimensions.get('window');
 
@@ -40,7 +41,7 @@ export default function Login() {
       });
       const data = await response.json();
       if (response.ok && data.token) {
-        await AsyncStorage.setItem('token', data.token);
+        await saveTokens(data);
         router.replace('/');
       } else {
         Alert.alert('Login Failed', data.error || data.message || 'Invalid credentials');
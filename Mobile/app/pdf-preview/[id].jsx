@@ -17,6 +17,7 @@ import {
 } from "react-native";
 import { LinearGradient } from "expo-linear-gradient";
 import { PDF_ROW_GENERATORS } from "../components/pdfGenerators";
+import { authFetch, clearTokens } from "../components/session";
 import { COLORS } from "../styles/colors";
 
 const { width, height } = Dimensions.get("window");
@@ -773,13 +774,12 @@ export default function PDFPreview() {
                       .replace(/[\n\r\t]+/g, " ")
                       .replace(/\s{2,}/g, " ")
                       .replace(/\"/g, '"')
-                      .trim();                    const response = await fetch(
+                      .trim();                    const response = await authFetch(
                       "https://interior-app-production.up.railway.app/api/worker/projects",
                       {
                         method: "POST",
                         headers: {
                           "Content-Type": "application/json",
-                          Authorization: `Bearer ${token}`,
                         },                        body: JSON.stringify({
                           clientName: project.clientName,
                           phone: project.phone,
@@ -803,6 +803,7 @@ export default function PDFPreview() {
                         router.replace("/");
                       }, 4000);
                     } else if (response.status === 401) {
+                      await clearTokens();
                       Alert.alert("Session Expired", "Please login again.");
                       router.replace("/login");
                     } else {
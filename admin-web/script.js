@@ -2,6 +2,10 @@ let authToken = localStorage.getItem("adminToken");
 let currentProject = null;
 let workers = [];
 let projects = [];
+let projectCounts = { total: 0, completed: 0 };
+// The project statuses and the transitions between them, so the status
+// pickers only offer moves the API allows
+let projectStatuses = { statuses: [], transitions: [] };
 
 const API_BASE_URL = "https://interior-app-production-3afe.up.railway.app/api";
 
@@ -73,7 +77,7 @@ async function handleLogin(e) {
     const data = await response.json();
 
     if (response.ok) {
-      localStorage.setItem("adminToken", data.token);
+      storeTokens(data);
       localStorage.setItem("adminUsername", username);
       showToast("Login successful!", "success");
 
@@ -123,7 +127,9 @@ async function loadDashboardData() {
   showLoading(true);
 
   try {
-    Promise.all([loadWorkers(), loadProjects()]).then(() => {
+    const loads = [loadWorkers(), loadProjects(), loadProjectStatuses()];
+    Promise.all(loads).then(() => {
+      renderProjectsTable();
       updateDashboardStats();
       loadRecentProjects();
     });
@@ -135,35 +141,84 @@ async function loadDashboardData() {
   }
 }
 
+// The API returns at most this many projects per request, so the list is
+// fetched page by page until X-Total-Count projects have arrived
+const PROJECT_PAGE_SIZE = 200;
+
 async function loadProjects() {
   try {
-    const response = await fetch(`${API_BASE_URL}/admin/projects`, {
-      headers: {
-        Authorization: `Bearer ${authToken}`,
-      },
-    });
+    const loaded = [];
+    let counts = null;
+    while (counts === null || loaded.length < counts.total) {
+      const response = await authFetch(
+        `${API_BASE_URL}/admin/projects?limit=${PROJECT_PAGE_SIZE}&offset=${loaded.length}`,
+      );
+      if (response.status === 401) {
+        handleUnauthorized();
+        return;
+      } else if (!response.ok) {
+        throw new Error("Failed to load projects");
+      }
 
-    if (response.ok) {
-      projects = await response.json();
-      renderProjectsTable();
-    } else if (response.status === 401) {
-      handleUnauthorized();
-    } else {
-      throw new Error("Failed to load projects");
+      const page = await response.json();
+      loaded.push(...page);
+      counts = {
+        total: Number(response.headers.get("X-Total-Count") ?? loaded.length),
+        completed: Number(
+          response.headers.get("X-Completed-Count") ??
+            loaded.filter((p) => p.isCompleted).length,
+        ),
+      };
+      // A short page is the last, even if projects were deleted meanwhile
+      if (page.length < PROJECT_PAGE_SIZE) {
+        break;
+      }
     }
+    projects = loaded;
+    projectCounts = counts;
+    renderProjectsTable();
   } catch (error) {
     console.error("Error loading projects:", error);
     projects = [];
   }
 }
 
+async function loadProjectStatuses() {
+  try {
+    const response = await fetch(`${API_BASE_URL}/project-statuses`);
+    if (!response.ok) {
+      throw new Error("Failed to load project statuses");
+    }
+    projectStatuses = await response.json();
+  } catch (error) {
+    console.error("Error loading project statuses:", error);
+  }
+}
+
+// Statuses an admin may move a project to from its current status
+function nextStatuses(status) {
+  return projectStatuses.transitions
+    .filter((t) => t.from === status && t.roles.includes("admin"))
+    .map((t) => t.to);
+}
+
+function statusLabel(status) {
+  return (status || "").replace(/_/g, " ");
+}
+
+// Options for a status picker: the current status, then the allowed moves
+function statusOptions(status) {
+  return [
+    `<option value="" selected>${statusLabel(status)}</option>`,
+    ...nextStatuses(status).map(
+      (next) => `<option value="${next}">Move to ${statusLabel(next)}</option>`,
+    ),
+  ].join("");
+}
+
 async function loadWorkers() {
   try {
-    const response = await fetch(`${API_BASE_URL}/admin/workers`, {
-      headers: {
-        Authorization: `Bearer ${authToken}`,
-      },
-    });
+    const response = await authFetch(`${API_BASE_URL}/admin/workers`);
 
     if (response.ok) {
       workers = await response.json();
@@ -180,8 +235,8 @@ async function loadWorkers() {
 }
 
 function updateDashboardStats() {
-  const totalProjects = projects.length;
-  const completedProjects = projects.filter((p) => p.isCompleted).length;
+  const totalProjects = projectCounts.total;
+  const completedProjects = projectCounts.completed;
   const pendingProjects = totalProjects - completedProjects;
   const totalWorkers = workers.length;
 
@@ -254,9 +309,9 @@ function renderProjectsTable() {
                         <button class="btn-small btn-view" onclick="viewProject('${project.id}')">
                             <i class="fas fa-eye"></i> View
                         </button>
-                        <button class="btn-small btn-toggle" onclick="toggleProjectStatus('${project.id}', ${!project.isCompleted})">
-                            <i class="fas fa-toggle-${project.isCompleted ? "on" : "off"}"></i>
-                        </button>
+                        <select class="status-select" onchange="changeProjectStatus('${project.id}', this.value)" ${nextStatuses(project.status).length ? "" : "disabled"}>
+                            ${statusOptions(project.status)}
+                        </select>
                         <button class="btn-small btn-delete" onclick="deleteProject('${project.id}', '${project.clientName}')">
                             <i class="fas fa-trash"></i> Delete
                         </button>
@@ -320,13 +375,8 @@ async function viewProject(projectId) {
     showLoading(true);
 
     // Fetch the specific project to get reconstructed HTML
-    const response = await fetch(
+    const response = await authFetch(
       `${API_BASE_URL}/admin/projects/${projectId}`,
-      {
-        headers: {
-          Authorization: `Bearer ${authToken}`,
-        },
-      },
     );
 
     if (!response.ok) {
@@ -352,13 +402,7 @@ async function viewProject(projectId) {
     document.getElementById("modalClientPhone").textContent = project.phone;
     document.getElementById("modalClientAddress").textContent = project.address;
 
-    const statusBadge = document.getElementById("modalProjectStatus");
-    statusBadge.textContent = project.isCompleted ? "Completed" : "Pending";
-    statusBadge.className = `status-badge ${project.isCompleted ? "status-completed" : "status-pending"}`;
-
-    // Update toggle button
-    const toggleBtn = document.getElementById("toggleStatusBtn");
-    toggleBtn.innerHTML = `<i class="fas fa-toggle-${project.isCompleted ? "on" : "off"}"></i> ${project.isCompleted ? "Mark Pending" : "Mark Complete"}`;
+    renderModalStatus(project);
     // Load HTML content with global.css applied
     const htmlContainer = document.getElementById("projectHtmlContainer");
     PDFUtils.displayProjectHTML(project, htmlContainer);
@@ -392,56 +436,56 @@ async function viewProject(projectId) {
   }
 }
 
-async function toggleProjectStatus(projectId, newStatus) {
-  if (!projectId) {
-    projectId = currentProject?.id;
-    newStatus = !currentProject?.isCompleted;
-  }
+function renderModalStatus(project) {
+  const statusBadge = document.getElementById("modalProjectStatus");
+  statusBadge.textContent = project.isCompleted ? "Completed" : "Pending";
+  statusBadge.className = `status-badge ${project.isCompleted ? "status-completed" : "status-pending"}`;
 
-  if (!projectId) {
-    showToast("Project not found", "error");
+  const statusSelect = document.getElementById("modalStatusSelect");
+  statusSelect.innerHTML = statusOptions(project.status);
+  statusSelect.disabled = nextStatuses(project.status).length === 0;
+}
+
+// Admin moves a project to the status picked in the table or the modal
+async function changeProjectStatus(projectId, status) {
+  if (!projectId || !status) {
     return;
   }
 
   showLoading(true);
 
   try {
-    const response = await fetch(
-      `${API_BASE_URL}/admin/projects/${projectId}/completed`,
+    const response = await authFetch(
+      `${API_BASE_URL}/admin/projects/${projectId}/status`,
       {
         method: "PUT",
         headers: {
-          Authorization: `Bearer ${authToken}`,
           "Content-Type": "application/json",
         },
-        body: JSON.stringify({ isCompleted: newStatus }),
+        body: JSON.stringify({ status }),
       },
     );
+    const data = await response.json();
 
     if (response.ok) {
-      showToast(
-        `Project marked as ${newStatus ? "completed" : "pending"}`,
-        "success",
-      );
-      await loadProjects();
-      updateDashboardStats();
-
-      // Update modal if open
-      if (currentProject && currentProject.id === projectId) {
-        currentProject.isCompleted = newStatus;
-        const statusBadge = document.getElementById("modalProjectStatus");
-        statusBadge.textContent = newStatus ? "Completed" : "Pending";
-        statusBadge.className = `status-badge ${newStatus ? "status-completed" : "status-pending"}`;
-
-        const toggleBtn = document.getElementById("toggleStatusBtn");
-        toggleBtn.innerHTML = `<i class="fas fa-toggle-${newStatus ? "on" : "off"}"></i> ${newStatus ? "Mark Pending" : "Mark Complete"}`;
-      }
+      showToast(`Project moved to ${statusLabel(data.status)}`, "success");
     } else {
-      const data = await response.json();
       showToast(data.error || "Failed to update project status", "error");
     }
+    // Reloading also puts a picker back on the current status after an error
+    await loadProjects();
+    updateDashboardStats();
+
+    if (currentProject && currentProject.id == projectId) {
+      const updated = projects.find((p) => p.id == projectId);
+      if (updated) {
+        currentProject.status = updated.status;
+        currentProject.isCompleted = updated.isCompleted;
+      }
+      renderModalStatus(currentProject);
+    }
   } catch (error) {
-    console.error("Error toggling project status:", error);
+    console.error("Error changing project status:", error);
     showToast("Network error. Please try again.", "error");
   } finally {
     showLoading(false);
@@ -461,14 +505,9 @@ async function deleteProject(projectId, clientName) {
   showLoading(true);
 
   try {
-    const response = await fetch(
+    const response = await authFetch(
       `${API_BASE_URL}/admin/projects/${projectId}`,
-      {
-        method: "DELETE",
-        headers: {
-          Authorization: `Bearer ${authToken}`,
-        },
-      },
+      { method: "DELETE" },
     );
     if (response.ok) {
       showToast("Project deleted successfully", "success");
@@ -520,10 +559,9 @@ async function handleCreateWorker(e) {
   showLoading(true);
 
   try {
-    const response = await fetch(`${API_BASE_URL}/admin/workers`, {
+    const response = await authFetch(`${API_BASE_URL}/admin/workers`, {
       method: "POST",
       headers: {
-        Authorization: `Bearer ${authToken}`,
         "Content-Type": "application/json",
       },
       body: JSON.stringify(workerData),
@@ -560,11 +598,8 @@ async function deleteWorker(workerId, workerName) {
   showLoading(true);
 
   try {
-    const response = await fetch(`${API_BASE_URL}/admin/workers/${workerId}`, {
+    const response = await authFetch(`${API_BASE_URL}/admin/workers/${workerId}`, {
       method: "DELETE",
-      headers: {
-        Authorization: `Bearer ${authToken}`,
-      },
     });
 
     if (response.ok) {
@@ -605,10 +640,9 @@ async function handleChangePassword(e) {
   showLoading(true);
 
   try {
-    const response = await fetch(`${API_BASE_URL}/admin/password`, {
+    const response = await authFetch(`${API_BASE_URL}/admin/password`, {
       method: "PUT",
       headers: {
-        Authorization: `Bearer ${authToken}`,
         "Content-Type": "application/json",
       },
       body: JSON.stringify({
@@ -713,9 +747,9 @@ function filterProjects() {
                         <button class="btn-small btn-view" onclick="viewProject('${project.id}')">
                             <i class="fas fa-eye"></i> View
                         </button>
-                        <button class="btn-small btn-toggle" onclick="toggleProjectStatus('${project.id}', ${!project.isCompleted})">
-                            <i class="fas fa-toggle-${project.isCompleted ? "on" : "off"}"></i>
-                        </button>
+                        <select class="status-select" onchange="changeProjectStatus('${project.id}', this.value)" ${nextStatuses(project.status).length ? "" : "disabled"}>
+                            ${statusOptions(project.status)}
+                        </select>
                         <button class="btn-small btn-delete" onclick="deleteProject('${project.id}', '${project.clientName}')">
                             <i class="fas fa-trash"></i> Delete
                         </button>
@@ -843,10 +877,15 @@ function refreshData() {
   showToast("Data refreshed", "success");
 }
 
-function logout() {
+async function logout() {
   if (confirm("Are you sure you want to logout?")) {
-    localStorage.removeItem("adminToken");
-    localStorage.removeItem("adminUsername");
+    try {
+      // End the session on the server so its refresh token stops working
+      await authFetch(`${API_BASE_URL}/admin/logout`, { method: "POST" });
+    } catch (error) {
+      console.error("Logout error:", error);
+    }
+    clearTokens();
     window.location.href = "index.html";
   } else {
     // Reload the page if cancel is clicked
@@ -857,12 +896,83 @@ function logout() {
 function handleUnauthorized() {
   showToast("Session expired. Please login again.", "error");
   setTimeout(() => {
-    localStorage.removeItem("adminToken");
-    localStorage.removeItem("adminUsername");
+    clearTokens();
     window.location.href = "index.html";
   }, 2000);
 }
 
+// Access tokens are short-lived; the refresh token from login or the last
+// refresh is exchanged for a new pair when the API answers 401
+function storeTokens(data) {
+  authToken = data.token;
+  localStorage.setItem("adminToken", data.token);
+  localStorage.setItem("adminRefreshToken", data.refreshToken);
+}
+
+function clearTokens() {
+  authToken = null;
+  localStorage.removeItem("adminToken");
+  localStorage.removeItem("adminRefreshToken");
+  localStorage.removeItem("adminUsername");
+}
+
+// Fetches an API URL with the access token, refreshing it and retrying once
+// if it has expired
+async function authFetch(url, options = {}) {
+  const send = () =>
+    fetch(url, {
+      ...options,
+      headers: { ...options.headers, Authorization: `Bearer ${authToken}` },
+    });
+  const response = await send();
+  if (response.status !== 401 || !(await refreshTokens())) {
+    return response;
+  }
+  return send();
+}
+
+let pendingRefresh = null;
+
+// Each refresh token works only once, so concurrent requests share one refresh
+function refreshTokens() {
+  if (!pendingRefresh) {
+    pendingRefresh = requestRefresh().finally(() => {
+      pendingRefresh = null;
+    });
+  }
+  return pendingRefresh;
+}
+
+async function requestRefresh() {
+  // Another tab may have refreshed already
+  const stored = localStorage.getItem("adminToken");
+  if (stored && stored !== authToken) {
+    authToken = stored;
+    return true;
+  }
+  const refreshToken = localStorage.getItem("adminRefreshToken");
+  if (!refreshToken) {
+    return false;
+  }
+  try {
+    const response = await fetch(`${API_BASE_URL}/auth/refresh`, {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify({ refreshToken }),
+    });
+    if (!response.ok) {
+      return false;
+    }
+    storeTokens(await response.json());
+    return true;
+  } catch (error) {
+    console.error("Token refresh error:", error);
+    return false;
+  }
+}
+
 // Download functionality for quotation content
 async function downloadQuotation(format) {
   if (!currentProject || !currentProject.html) {
@@ -932,13 +1042,8 @@ async function generateStitchingQuotation() {
   try {
     showLoading(true);
 
-    const response = await fetch(
+    const response = await authFetch(
       `${API_BASE_URL}/admin/projects/${currentProject.id}/stitching-quotation`,
-      {
-        headers: {
-          Authorization: `Bearer ${authToken}`,
-        },
-      },
     );
 
     if (!response.ok) {
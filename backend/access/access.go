@@ -0,0 +1,62 @@
+// Package access defines the roles an admin can have in an organisation and
+// the permissions each role grants. Admin routes declare the permission they
+// need; see middleware.Require.
+package access
+
+// Admin roles, from most to least privileged
+const (
+	Owner      = "owner"
+	Manager    = "manager"
+	Accountant = "accountant"
+	ReadOnly   = "read_only"
+)
+
+// Roles lists every admin role
+var Roles = []string{Owner, Manager, Accountant, ReadOnly}
+
+// Permission is something an admin route lets its caller do
+type Permission string
+
+const (
+	// ViewData covers reading projects, workers, the catalog and templates
+	ViewData Permission = "data:view"
+	// ManageWorkers covers creating, editing and deleting worker accounts
+	ManageWorkers Permission = "workers:manage"
+	// ManageProjects covers deleting, restoring and completing projects
+	ManageProjects Permission = "projects:manage"
+	// ChangeProjectStatus covers moving projects through their lifecycle
+	ChangeProjectStatus Permission = "projects:status"
+	// ManageCatalog covers brands, folders and cloths
+	ManageCatalog Permission = "catalog:manage"
+	// ManageTemplates covers quotation branding and stitching sheet templates
+	ManageTemplates Permission = "templates:manage"
+	// ViewAudit covers the audit log and failed logins
+	ViewAudit Permission = "audit:view"
+	// ManageOrganisation covers the organisation itself and its admins
+	ManageOrganisation Permission = "organisation:manage"
+)
+
+// Grants lists the permissions of each role. Accountants handle quotations and
+// payments; read-only admins can look but not change anything.
+var Grants = map[string][]Permission{
+	Owner:      {ViewData, ManageWorkers, ManageProjects, ChangeProjectStatus, ManageCatalog, ManageTemplates, ViewAudit, ManageOrganisation},
+	Manager:    {ViewData, ManageWorkers, ManageProjects, ChangeProjectStatus, ManageCatalog, ManageTemplates, ViewAudit},
+	Accountant: {ViewData, ChangeProjectStatus, ManageTemplates},
+	ReadOnly:   {ViewData},
+}
+
+// ValidRole reports whether role is a known admin role
+func ValidRole(role string) bool {
+	_, ok := Grants[role]
+	return ok
+}
+
+// Allowed reports whether role grants p
+func Allowed(role string, p Permission) bool {
+	for _, g := range Grants[role] {
+		if g == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/access"
+	"github.com/Vanaraj10/interior-backend/config"
+	"github.com/Vanaraj10/interior-backend/handlers"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+const adminUsage = `usage: admin create --username NAME [--password PASSWORD] [--organisation NAME | --organisation-id ID --role ROLE]
+       admin invite [--note TEXT] [--expires DURATION]`
+
+// runAdminCommand handles `admin create` and `admin invite`
+func runAdminCommand(s *store.Store, cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		fmt.Println(adminUsage)
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "create":
+		createAdminCommand(s, cfg, args[1:])
+	case "invite":
+		inviteCommand(s, cfg, args[1:])
+	default:
+		fmt.Println(adminUsage)
+		os.Exit(2)
+	}
+}
+
+// createAdminCommand creates an admin. Without --organisation-id the admin
+// becomes the owner of a new organisation. The password may be given in
+// ADMIN_PASSWORD instead, to keep it out of the shell history.
+func createAdminCommand(s *store.Store, cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("admin create", flag.ExitOnError)
+	username := fs.String("username", "", "login name of the new admin")
+	password := fs.String("password", os.Getenv("ADMIN_PASSWORD"), "password of the new admin (default $ADMIN_PASSWORD)")
+	orgName := fs.String("organisation", "", "name of the new organisation (default the username)")
+	orgID := fs.Int("organisation-id", 0, "add the admin to this existing organisation instead")
+	role := fs.String("role", access.Owner, "role of the admin in an existing organisation")
+	fs.Parse(args)
+
+	if *username == "" {
+		log.Fatal("admin create: --username is required")
+	}
+	if err := handlers.CheckPassword(*password, *username, cfg.Auth.PasswordMinLength); err != nil {
+		log.Fatalf("admin create: %v", err)
+	}
+	if !access.ValidRole(*role) {
+		log.Fatalf("admin create: unknown role %q", *role)
+	}
+	hash, err := handlers.HashPassword(*password)
+	if err != nil {
+		log.Fatalf("admin create: %v", err)
+	}
+	admin := models.Admin{Username: *username, PasswordHash: hash, Role: *role}
+	if *orgID == 0 {
+		if *role != access.Owner {
+			log.Fatal("admin create: the first admin of a new organisation must be an owner")
+		}
+		org := models.Organisation{Name: *orgName}
+		if org.Name == "" {
+			org.Name = *username
+		}
+		err = s.Admins.CreateOrganisation(&org, &admin)
+	} else {
+		if _, err := s.Admins.GetOrganisation(*orgID); err != nil {
+			log.Fatalf("admin create: organisation %d: %v", *orgID, err)
+		}
+		admin.OrganisationID = *orgID
+		err = s.Admins.CreateAdmin(&admin)
+	}
+	if err == store.ErrConflict {
+		log.Fatalf("admin create: username %q is already taken", *username)
+	} else if err != nil {
+		log.Fatalf("admin create: %v", err)
+	}
+	fmt.Printf("Created %s %q (id %d) in organisation %d\n", admin.Role, admin.Username, admin.ID, admin.OrganisationID)
+}
+
+// inviteCommand creates a signup invite for a new organisation and prints its
+// code, which cannot be shown again
+func inviteCommand(s *store.Store, cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("admin invite", flag.ExitOnError)
+	note := fs.String("note", "", "who the invite is for")
+	expires := fs.Duration("expires", cfg.Setup.InviteLifetime, "how long the invite can be used")
+	fs.Parse(args)
+
+	if *expires <= 0 {
+		log.Fatal("admin invite: --expires must be positive")
+	}
+	invite, code, err := handlers.NewInvite(*note, *expires)
+	if err != nil {
+		log.Fatalf("admin invite: %v", err)
+	}
+	if err := s.Admins.CreateInvite(invite); err != nil {
+		log.Fatalf("admin invite: %v", err)
+	}
+	fmt.Printf("Invite code: %s\nExpires: %s\n", code, invite.ExpiresAt.Format(time.RFC3339))
+}
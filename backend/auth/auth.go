@@ -0,0 +1,189 @@
+// Package auth signs and verifies the API's access tokens. Every token carries
+// the id of the key that signed it in its kid header, and is only accepted
+// with the configured algorithm and a known key.
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Vanaraj10/interior-backend/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for a token that is malformed, expired, signed
+// with another algorithm or by an unknown key
+var ErrInvalidToken = errors.New("invalid token")
+
+// key is one signing key. For HS256 the secret both signs and verifies; for
+// RS256 and EdDSA sign is the private key and verify its public key.
+type key struct {
+	id     string
+	sign   interface{}
+	verify interface{}
+}
+
+// Keys signs tokens with the current key, the first, and verifies them with
+// any of its keys
+type Keys struct {
+	method jwt.SigningMethod
+	keys   []key
+	byID   map[string]key
+}
+
+// New loads the keys described by cfg. In development, a missing secret or
+// key file is replaced by a throwaway key, so tokens do not survive a restart.
+func New(cfg config.AuthConfig) (*Keys, error) {
+	var keys []key
+	var err error
+	switch cfg.Algorithm {
+	case "HS256":
+		keys, err = hmacKeys(cfg)
+	case "RS256", "EdDSA":
+		keys, err = keyFiles(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", cfg.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	k := &Keys{method: jwt.GetSigningMethod(cfg.Algorithm), keys: keys, byID: make(map[string]key)}
+	for _, key := range keys {
+		k.byID[key.id] = key
+	}
+	return k, nil
+}
+
+func hmacKeys(cfg config.AuthConfig) ([]key, error) {
+	secrets := cfg.PreviousJWTSecrets
+	if cfg.JWTSecret != "" {
+		secrets = append([]string{cfg.JWTSecret}, secrets...)
+	} else {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		log.Println("WARNING: JWT_SECRET is not set, signing tokens with a temporary secret")
+		secrets = append([]string{string(b)}, secrets...)
+	}
+	keys := make([]key, len(secrets))
+	for i, s := range secrets {
+		// The id is derived from the secret so that it stays the same across
+		// restarts, without revealing the secret itself
+		sum := sha256.Sum256([]byte("kid:" + s))
+		keys[i] = key{id: hex.EncodeToString(sum[:8]), sign: []byte(s), verify: []byte(s)}
+	}
+	return keys, nil
+}
+
+func keyFiles(cfg config.AuthConfig) ([]key, error) {
+	if len(cfg.KeyFiles) == 0 {
+		log.Printf("WARNING: JWT_KEY_FILES is not set, signing tokens with a temporary %s key", cfg.Algorithm)
+		priv, err := generateKey(cfg.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		k, err := asymmetricKey(priv, cfg.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		return []key{k}, nil
+	}
+	var keys []key
+	for _, path := range cfg.KeyFiles {
+		priv, err := readPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		k, err := asymmetricKey(priv, cfg.Algorithm)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func generateKey(alg string) (crypto.Signer, error) {
+	if alg == "RS256" {
+		return rsa.GenerateKey(rand.Reader, 2048)
+	}
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	return priv, err
+}
+
+// readPrivateKey reads a PKCS #8 (RSA or Ed25519) or PKCS #1 (RSA) PEM file
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+	if priv, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return priv, nil
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: parsing private key: %w", path, err)
+	}
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported private key type %T", path, priv)
+	}
+	return signer, nil
+}
+
+func asymmetricKey(priv crypto.Signer, alg string) (key, error) {
+	switch pub := priv.Public().(type) {
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return key{}, fmt.Errorf("an RSA key cannot sign %s tokens", alg)
+		}
+		return key{id: thumbprint(pub), sign: priv, verify: pub}, nil
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			return key{}, fmt.Errorf("an Ed25519 key cannot sign %s tokens", alg)
+		}
+		return key{id: thumbprint(pub), sign: priv, verify: pub}, nil
+	default:
+		return key{}, fmt.Errorf("unsupported key type %T", pub)
+	}
+}
+
+// Sign returns a token for claims signed with the current key
+func (k *Keys) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.keys[0].id
+	return token.SignedString(k.keys[0].sign)
+}
+
+// Parse verifies a token and returns its claims. Only the configured algorithm
+// is accepted, and the token's kid must name one of the keys.
+func (k *Keys) Parse(tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		id, _ := token.Header["kid"].(string)
+		key, ok := k.byID[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", id)
+		}
+		return key.verify, nil
+	}, jwt.WithValidMethods([]string{k.method.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
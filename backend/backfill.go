@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Vanaraj10/interior-backend/handlers"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/pricing"
+	"github.com/Vanaraj10/interior-backend/search"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+const backfillBatchSize = 100
+
+// runBackfillCommand handles `backfill measurements`, `backfill search` and
+// `backfill customers`
+func runBackfillCommand(s *store.Store, args []string) {
+	if len(args) == 0 {
+		fmt.Println("usage: backfill measurements|search|customers")
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "measurements":
+		backfillMeasurements(s)
+	case "search":
+		backfillSearch(s)
+	case "customers":
+		backfillCustomers(s)
+	default:
+		fmt.Println("usage: backfill measurements|search|customers")
+		os.Exit(2)
+	}
+}
+
+// eachProject calls fn with every project, deleted ones included, in id order
+func eachProject(s *store.Store, fn func(p *models.Project)) {
+	after := 0
+	for {
+		projects, err := s.Projects.ListProjectsAfter(after, backfillBatchSize)
+		if err != nil {
+			log.Fatalf("backfill: %v", err)
+		}
+		if len(projects) == 0 {
+			return
+		}
+		for i := range projects {
+			after = projects[i].ID
+			fn(&projects[i])
+		}
+	}
+}
+
+func backfillMeasurements(s *store.Store) {
+	var synced, skipped int
+	eachProject(s, func(p *models.Project) {
+		rooms, measurements, err := pricing.RowsFromRawData(p.RawData)
+		if err != nil {
+			// Leave rows of unreadable projects as they are and carry on
+			log.Printf("project %d: %v", p.ID, err)
+			skipped++
+			return
+		}
+		if err := s.Measurements.ReplaceProjectMeasurements(p.ID, rooms, measurements); err != nil {
+			log.Fatalf("backfill measurements: project %d: %v", p.ID, err)
+		}
+		synced++
+	})
+	fmt.Printf("Backfilled %d project(s), skipped %d with invalid rawData\n", synced, skipped)
+}
+
+// backfillSearch indexes every project, for data saved before the search
+// index existed
+func backfillSearch(s *store.Store) {
+	indexed := 0
+	eachProject(s, func(p *models.Project) {
+		if err := s.Search.ReplaceProjectTerms(p.ID, p.OrganisationID, search.Terms(p)); err != nil {
+			log.Fatalf("backfill search: project %d: %v", p.ID, err)
+		}
+		indexed++
+	})
+	fmt.Printf("Indexed %d project(s)\n", indexed)
+}
+
+// backfillCustomers links every project to its customer, creating customers
+// from projects saved before they existed. Projects are visited oldest first,
+// so each customer ends up with the details of their latest project.
+func backfillCustomers(s *store.Store) {
+	linked := 0
+	eachProject(s, func(p *models.Project) {
+		if err := handlers.LinkCustomer(s, p); err != nil {
+			log.Fatalf("backfill customers: project %d: %v", p.ID, err)
+		}
+		if p.CustomerID != nil {
+			linked++
+		}
+	})
+	fmt.Printf("Linked %d project(s) to customers\n", linked)
+}
@@ -0,0 +1,352 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the full application configuration. It is loaded once at startup
+// and passed to the components that need it.
+type Config struct {
+	Env       string          `yaml:"env"`
+	Port      string          `yaml:"port"`
+	Store     StoreConfig     `yaml:"store"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Auth      AuthConfig      `yaml:"auth"`
+	CORS      CORSConfig      `yaml:"cors"`
+	SeedAdmin SeedAdminConfig `yaml:"seedAdmin"`
+	Purge     PurgeConfig     `yaml:"purge"`
+	Login     LoginConfig     `yaml:"login"`
+	Setup     SetupConfig     `yaml:"setup"`
+}
+
+// StoreConfig selects the storage backend ("mssql", "postgres" or "memory")
+type StoreConfig struct {
+	Driver string `yaml:"driver"`
+}
+
+// DatabaseConfig holds the connection string and pool settings
+type DatabaseConfig struct {
+	DSN             string        `yaml:"dsn"`
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+}
+
+// AuthConfig holds token signing settings. Access tokens are short-lived JWTs;
+// refresh tokens are stored server-side and replaced on every use.
+//
+// HS256 tokens are signed with JWTSecret; PreviousJWTSecrets are still
+// accepted so the secret can be rotated. RS256 and EdDSA tokens are signed
+// with the first PEM private key in KeyFiles and verified with any of them.
+type AuthConfig struct {
+	Algorithm            string        `yaml:"algorithm"`
+	JWTSecret            string        `yaml:"jwtSecret"`
+	PreviousJWTSecrets   []string      `yaml:"previousJwtSecrets"`
+	KeyFiles             []string      `yaml:"keyFiles"`
+	AccessTokenLifetime  time.Duration `yaml:"accessTokenLifetime"`
+	RefreshTokenLifetime time.Duration `yaml:"refreshTokenLifetime"`
+	PasswordMinLength    int           `yaml:"passwordMinLength"`
+}
+
+// CORSConfig lists the origins allowed to call the API
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+}
+
+// SeedAdminConfig is the admin created at startup by the in-memory store
+type SeedAdminConfig struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// PurgeConfig controls when deleted projects and workers are removed for good.
+// Rows are purged once they have been deleted for Retention; with a positive
+// Interval the server does this in the background every Interval.
+type PurgeConfig struct {
+	Retention time.Duration `yaml:"retention"`
+	Interval  time.Duration `yaml:"interval"`
+}
+
+// LoginConfig limits password guessing. After each failed login the next
+// attempt for the same username or IP must wait Backoff, doubled for every
+// further failure; MaxFailures failures for a username, or IPMaxFailures for
+// an IP, lock it out for Lockout.
+type LoginConfig struct {
+	MaxFailures   int           `yaml:"maxFailures"`
+	IPMaxFailures int           `yaml:"ipMaxFailures"`
+	Backoff       time.Duration `yaml:"backoff"`
+	Lockout       time.Duration `yaml:"lockout"`
+}
+
+// SetupConfig controls how new organisations are created over the API. With
+// Enabled, /api/setup creates the first admin while no admin exists; Token, if
+// set, must be sent with it. Signup invites expire after InviteLifetime.
+type SetupConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	Token          string        `yaml:"token"`
+	InviteLifetime time.Duration `yaml:"inviteLifetime"`
+}
+
+// Default returns the configuration used before the file and environment are applied
+func Default() *Config {
+	return &Config{
+		Env:   "development",
+		Port:  "8080",
+		Store: StoreConfig{Driver: "mssql"},
+		Database: DatabaseConfig{
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Auth: AuthConfig{
+			Algorithm:            "HS256",
+			AccessTokenLifetime:  15 * time.Minute,
+			RefreshTokenLifetime: 30 * 24 * time.Hour,
+			PasswordMinLength:    8,
+		},
+		CORS:  CORSConfig{AllowedOrigins: []string{"*"}},
+		Purge: PurgeConfig{Retention: 30 * 24 * time.Hour},
+		Login: LoginConfig{
+			MaxFailures:   5,
+			IPMaxFailures: 20,
+			Backoff:       time.Second,
+			Lockout:       15 * time.Minute,
+		},
+		Setup: SetupConfig{InviteLifetime: 7 * 24 * time.Hour},
+	}
+}
+
+// Load builds the configuration from defaults, an optional YAML file and the
+// environment (including a .env file), in increasing order of precedence.
+// The YAML file is read from CONFIG_FILE, or config.yaml if that exists.
+func Load() (*Config, error) {
+	godotenv.Load()
+
+	cfg := Default()
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat("config.yaml"); err == nil {
+			path = "config.yaml"
+		}
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	setString(&c.Env, "APP_ENV")
+	setString(&c.Port, "PORT")
+	setString(&c.Store.Driver, "STORE_DRIVER")
+	setString(&c.Database.DSN, "DATABASE_DSN")
+	setString(&c.Auth.Algorithm, "JWT_ALGORITHM")
+	setString(&c.Auth.JWTSecret, "JWT_SECRET")
+	setString(&c.SeedAdmin.Username, "SEED_ADMIN_USERNAME")
+	setString(&c.SeedAdmin.Password, "SEED_ADMIN_PASSWORD")
+	setString(&c.Setup.Token, "SETUP_TOKEN")
+	if v := os.Getenv("CORS_ORIGINS"); v != "" {
+		c.CORS.AllowedOrigins = splitList(v)
+	}
+	if v := os.Getenv("JWT_PREVIOUS_SECRETS"); v != "" {
+		c.Auth.PreviousJWTSecrets = splitList(v)
+	}
+	if v := os.Getenv("JWT_KEY_FILES"); v != "" {
+		c.Auth.KeyFiles = splitList(v)
+	}
+	if err := setInt(&c.Database.MaxOpenConns, "DB_MAX_OPEN_CONNS"); err != nil {
+		return err
+	}
+	if err := setInt(&c.Database.MaxIdleConns, "DB_MAX_IDLE_CONNS"); err != nil {
+		return err
+	}
+	if err := setDuration(&c.Database.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME"); err != nil {
+		return err
+	}
+	if err := setDuration(&c.Auth.AccessTokenLifetime, "ACCESS_TOKEN_LIFETIME"); err != nil {
+		return err
+	}
+	if err := setDuration(&c.Auth.RefreshTokenLifetime, "REFRESH_TOKEN_LIFETIME"); err != nil {
+		return err
+	}
+	if err := setInt(&c.Auth.PasswordMinLength, "PASSWORD_MIN_LENGTH"); err != nil {
+		return err
+	}
+	if err := setDuration(&c.Purge.Retention, "PURGE_RETENTION"); err != nil {
+		return err
+	}
+	if err := setDuration(&c.Purge.Interval, "PURGE_INTERVAL"); err != nil {
+		return err
+	}
+	if err := setInt(&c.Login.MaxFailures, "LOGIN_MAX_FAILURES"); err != nil {
+		return err
+	}
+	if err := setInt(&c.Login.IPMaxFailures, "LOGIN_IP_MAX_FAILURES"); err != nil {
+		return err
+	}
+	if err := setDuration(&c.Login.Backoff, "LOGIN_BACKOFF"); err != nil {
+		return err
+	}
+	if err := setDuration(&c.Login.Lockout, "LOGIN_LOCKOUT"); err != nil {
+		return err
+	}
+	if err := setBool(&c.Setup.Enabled, "SETUP_ENABLED"); err != nil {
+		return err
+	}
+	return setDuration(&c.Setup.InviteLifetime, "INVITE_LIFETIME")
+}
+
+// Validate reports every configuration problem at once
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Env != "development" && c.Env != "production" {
+		errs = append(errs, fmt.Errorf("env must be development or production, got %q", c.Env))
+	}
+	if p, err := strconv.Atoi(c.Port); err != nil || p <= 0 || p > 65535 {
+		errs = append(errs, fmt.Errorf("port must be a number between 1 and 65535, got %q", c.Port))
+	}
+	switch c.Store.Driver {
+	case "mssql", "postgres":
+		if c.Database.DSN == "" {
+			errs = append(errs, fmt.Errorf("database dsn (DATABASE_DSN) is required for the %s store", c.Store.Driver))
+		}
+	case "memory":
+	default:
+		errs = append(errs, fmt.Errorf("store driver must be mssql, postgres or memory, got %q", c.Store.Driver))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database pool sizes must not be negative"))
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, errors.New("database maxIdleConns must not exceed maxOpenConns"))
+	}
+	// Without keys a development server signs with a throwaway key instead
+	switch c.Auth.Algorithm {
+	case "HS256":
+		if c.Auth.JWTSecret == "" && c.Env == "production" {
+			errs = append(errs, errors.New("auth jwtSecret (JWT_SECRET) is required in production"))
+		}
+	case "RS256", "EdDSA":
+		if len(c.Auth.KeyFiles) == 0 && c.Env == "production" {
+			errs = append(errs, fmt.Errorf("auth keyFiles (JWT_KEY_FILES) are required for %s in production", c.Auth.Algorithm))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("auth algorithm must be HS256, RS256 or EdDSA, got %q", c.Auth.Algorithm))
+	}
+	if c.Auth.AccessTokenLifetime <= 0 || c.Auth.RefreshTokenLifetime <= 0 {
+		errs = append(errs, errors.New("auth token lifetimes must be positive"))
+	} else if c.Auth.AccessTokenLifetime > c.Auth.RefreshTokenLifetime {
+		errs = append(errs, errors.New("auth accessTokenLifetime must not exceed refreshTokenLifetime"))
+	}
+	// bcrypt ignores everything after 72 bytes
+	if c.Auth.PasswordMinLength < 1 || c.Auth.PasswordMinLength > 72 {
+		errs = append(errs, errors.New("auth passwordMinLength must be between 1 and 72"))
+	}
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("at least one CORS origin is required"))
+	}
+	if c.Purge.Retention <= 0 {
+		errs = append(errs, errors.New("purge retention must be positive"))
+	}
+	if c.Purge.Interval < 0 {
+		errs = append(errs, errors.New("purge interval must not be negative"))
+	}
+	if c.Login.MaxFailures <= 0 || c.Login.IPMaxFailures <= 0 {
+		errs = append(errs, errors.New("login maxFailures and ipMaxFailures must be positive"))
+	}
+	if c.Login.Backoff < 0 {
+		errs = append(errs, errors.New("login backoff must not be negative"))
+	}
+	if c.Login.Lockout <= 0 {
+		errs = append(errs, errors.New("login lockout must be positive"))
+	}
+	if c.Setup.Enabled && c.Setup.Token == "" && c.Env == "production" {
+		errs = append(errs, errors.New("setup token (SETUP_TOKEN) is required to enable setup in production"))
+	}
+	if c.Setup.InviteLifetime <= 0 {
+		errs = append(errs, errors.New("setup inviteLifetime must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+func setString(dst *string, key string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func setInt(dst *int, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s must be an integer: %w", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+func setBool(dst *bool, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%s must be true or false: %w", key, err)
+	}
+	*dst = b
+	return nil
+}
+
+func setDuration(dst *time.Duration, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration such as 30m or 720h: %w", key, err)
+	}
+	*dst = d
+	return nil
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
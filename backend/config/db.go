@@ -3,50 +3,34 @@ package config
 import (
 	"database/sql"
 	"fmt"
-	"log"
-	"os"
-	"sync"
 
 	_ "github.com/denisenkom/go-mssqldb"
+	_ "github.com/lib/pq"
 )
 
-var (
-	db   *sql.DB
-	once sync.Once
-)
-
-func ConnectAzureSQL() *sql.DB {
-	server := "zyntriqtechnologies.database.windows.net"
-	port := 1433
-	user := "vj2303"
-	password := os.Getenv("DB_PASS") // Replace with your actual password or use env var
-	database := "interior"
-
-	connString := fmt.Sprintf("server=%s;user id=%s;password=%s;port=%d;database=%s;encrypt=true;TrustServerCertificate=false;", server, user, password, port, database)
+// sqlDrivers maps store drivers to the database/sql drivers they connect with
+var sqlDrivers = map[string]string{
+	"mssql":    "sqlserver",
+	"postgres": "postgres",
+}
 
-	var err error
-	db, err = sql.Open("sqlserver", connString)
+// OpenDB opens and pings the database of a store driver ("mssql" or
+// "postgres") described by cfg
+func OpenDB(driver string, cfg DatabaseConfig) (*sql.DB, error) {
+	sqlDriver, ok := sqlDrivers[driver]
+	if !ok {
+		return nil, fmt.Errorf("store driver %q does not use a database", driver)
+	}
+	db, err := sql.Open(sqlDriver, cfg.DSN)
 	if err != nil {
-		log.Fatalf("Azure SQL connection error: %v", err)
+		return nil, fmt.Errorf("opening database: %w", err)
 	}
-	if err = db.Ping(); err != nil {
-		log.Fatalf("Azure SQL ping error: %v", err)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
 	}
-	log.Println("Connected to Azure SQL!")
-	return db
-}
-
-func GetDB() *sql.DB {
-	once.Do(func() {
-		var err error
-		connString := "server=zyntriqtechnologies.database.windows.net;port=1433;user id=vj2303;password=Letmelive@123;database=interior;encrypt=true"
-		db, err = sql.Open("sqlserver", connString)
-		if err != nil {
-			log.Fatalf("Failed to open Azure SQL connection: %v", err)
-		}
-		if err = db.Ping(); err != nil {
-			log.Fatalf("Failed to connect to Azure SQL: %v", err)
-		}
-	})
-	return db
+	return db, nil
 }
@@ -1,17 +1,16 @@
 package handlers
 
 import (
-	"database/sql"
+	"log"
 	"net/http"
 
-	"github.com/Vanaraj10/interior-backend/config"
-	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // Admin changes their password
-func ChangeAdminPassword(c *gin.Context) {
+func (h *Handler) ChangeAdminPassword(c *gin.Context) {
 	adminId := c.GetInt("admin_id")
 	var req struct {
 		OldPassword string `json:"old_password"`
@@ -21,10 +20,8 @@ func ChangeAdminPassword(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
-	db := config.GetDB()
-	var admin models.Admin
-	err := db.QueryRow(`SELECT id, username, password_hash, created_at FROM admins WHERE id = @p1`, adminId).Scan(&admin.ID, &admin.Username, &admin.PasswordHash, &admin.CreatedAt)
-	if err == sql.ErrNoRows {
+	admin, err := h.store.Admins.GetAdmin(adminId)
+	if err == store.ErrNotFound {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
 		return
 	} else if err != nil {
@@ -35,11 +32,18 @@ func ChangeAdminPassword(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Old password incorrect"})
 		return
 	}
+	if err := h.checkPassword(req.NewPassword, admin.Username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	hash, _ := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
-	_, err = db.Exec(`UPDATE admins SET password_hash = @p1 WHERE id = @p2`, string(hash), adminId)
-	if err != nil {
+	if err := h.store.Admins.UpdateAdminPassword(adminId, string(hash)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
 		return
 	}
+	// Sign out the admin's other devices, which may be why the password changed
+	if _, err := h.store.Tokens.RevokeOtherSessions("admin", adminId, c.GetString("session_id")); err != nil {
+		log.Printf("Failed to revoke other sessions of admin %d: %v", adminId, err)
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Password updated"})
 }
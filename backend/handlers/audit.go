@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLoaders returns the loaders the audit middleware uses to snapshot
+// each entity type before and after a change
+func (h *Handler) AuditLoaders() map[string]func(id, orgID int) (interface{}, error) {
+	return map[string]func(id, orgID int) (interface{}, error){
+		"project": func(id, orgID int) (interface{}, error) {
+			p, err := h.store.Projects.GetProject(id, orgID)
+			if err != nil {
+				return nil, err
+			}
+			// The HTML is kept in the project's revisions; leave it out of the log
+			p.HTML = ""
+			return p, nil
+		},
+		"customer": func(id, orgID int) (interface{}, error) {
+			return h.store.Customers.GetCustomer(id, orgID)
+		},
+		"worker": func(id, orgID int) (interface{}, error) {
+			return h.store.Workers.GetWorker(id, orgID)
+		},
+		"brand": func(id, orgID int) (interface{}, error) {
+			return h.store.Catalog.GetBrand(id, orgID)
+		},
+		"folder": func(id, orgID int) (interface{}, error) {
+			return h.store.Catalog.GetFolder(id, orgID)
+		},
+		"cloth": func(id, orgID int) (interface{}, error) {
+			return h.store.Catalog.GetCloth(id, orgID)
+		},
+		"admin": func(id, orgID int) (interface{}, error) {
+			a, err := h.store.Admins.GetAdmin(id)
+			if err == nil && a.OrganisationID != orgID {
+				return nil, store.ErrNotFound
+			}
+			return a, err
+		},
+	}
+}
+
+// parseRef splits a filter such as "project:12" into its type and optional id
+func parseRef(v string) (string, *int, bool) {
+	name, idStr, hasID := strings.Cut(v, ":")
+	if !hasID {
+		return name, nil, true
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return "", nil, false
+	}
+	return name, &id, true
+}
+
+// queryTime reads a query parameter as an RFC 3339 timestamp or a date
+// (YYYY-MM-DD); it returns nil if the parameter is not set. A date read as an
+// exclusive end is the start of the next day, so the whole date is included.
+func queryTime(c *gin.Context, name string, end bool) (*time.Time, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02", v, time.Local)
+		if err == nil && end {
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Admin lists the audit log of their team a page at a time, newest first.
+// entity is a type or type:id (e.g. project:12), actor a role or role:id
+// (e.g. worker:3), and from/to bound the time as RFC 3339 or YYYY-MM-DD.
+func (h *Handler) ListAudit(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	q := store.AuditQuery{Limit: limit, Offset: offset}
+	if v := c.Query("entity"); v != "" {
+		var ok bool
+		if q.EntityType, q.EntityID, ok = parseRef(v); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity, expected type or type:id"})
+			return
+		}
+	}
+	if v := c.Query("actor"); v != "" {
+		var ok bool
+		if q.ActorRole, q.ActorID, ok = parseRef(v); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor, expected role or role:id"})
+			return
+		}
+	}
+	if q.From, err = queryTime(c, "from", false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from timestamp, expected RFC 3339 or YYYY-MM-DD"})
+		return
+	}
+	if q.To, err = queryTime(c, "to", true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to timestamp, expected RFC 3339 or YYYY-MM-DD"})
+		return
+	}
+	entries, total, err := h.store.Audit.ListAudit(orgId, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit log"})
+		return
+	}
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
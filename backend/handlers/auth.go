@@ -1,84 +1,363 @@
 package handlers
 
 import (
-	"database/sql"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
-	"os"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/Vanaraj10/interior-backend/config"
+	"github.com/Vanaraj10/interior-backend/lockout"
 	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET")) // Use env var in production
-
 type LoginRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
+// LoginResponse carries a short-lived access token and the refresh token
+// that is exchanged for the next pair at /api/auth/refresh
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"`
 }
 
 // Admin login
-func AdminLogin(c *gin.Context) {
+func (h *Handler) AdminLogin(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Println("BindJSON error:", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
-	db := config.GetDB()
-	var admin models.Admin
-	err := db.QueryRow(`SELECT id, username, password_hash, created_at FROM admins WHERE username = @p1`, req.Username).Scan(&admin.ID, &admin.Username, &admin.PasswordHash, &admin.CreatedAt)
-	if err != nil {
-		log.Println("DB lookup error for username:", req.Username, "err:", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
-		return
-	}
-	if err := bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(req.Password)); err != nil {
-		log.Println("Password mismatch for username:", req.Username)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+	admin, ok := h.checkLogin(c, "admin", req, func() (*account, error) {
+		a, err := h.store.Admins.GetAdminByUsername(req.Username)
+		if err != nil {
+			return nil, err
+		}
+		return &account{ID: a.ID, OrganisationID: a.OrganisationID, PasswordHash: a.PasswordHash, Active: true}, nil
+	})
+	if !ok {
 		return
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": admin.ID,
-		"role":    "admin",
-		"exp":     time.Now().Add(30 * 24 * time.Hour).Unix(),
-	})
-	tokenString, _ := token.SignedString(jwtSecret)
-	c.JSON(http.StatusOK, LoginResponse{Token: tokenString})
+	h.issueTokens(c, models.RefreshToken{Role: "admin", UserID: admin.ID, OrganisationID: admin.OrganisationID}, 0)
 }
 
 // Worker login
-func WorkerLogin(c *gin.Context) {
+func (h *Handler) WorkerLogin(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
-	db := config.GetDB()
-	var worker models.Worker
-	err := db.QueryRow(`SELECT id, username, password_hash, admin_id, name, phone, created_at FROM workers WHERE username = @p1`, req.Username).Scan(&worker.ID, &worker.Username, &worker.PasswordHash, &worker.AdminID, &worker.Name, &worker.Phone, &worker.CreatedAt)
-	if err == sql.ErrNoRows || err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+	worker, ok := h.checkLogin(c, "worker", req, func() (*account, error) {
+		w, err := h.store.Workers.GetWorkerByUsername(req.Username)
+		if err != nil {
+			return nil, err
+		}
+		return &account{ID: w.ID, OrganisationID: w.OrganisationID, PasswordHash: w.PasswordHash, Active: w.IsActive}, nil
+	})
+	if !ok {
 		return
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(worker.PasswordHash), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+	h.issueTokens(c, models.RefreshToken{Role: "worker", UserID: worker.ID, OrganisationID: worker.OrganisationID}, 0)
+}
+
+// account is the part of an admin or worker that login needs
+type account struct {
+	ID             int
+	OrganisationID int
+	PasswordHash   string
+	Active         bool
+}
+
+// unknownUserHash is compared against when the username does not exist. Its
+// cost matches HashPassword.
+var unknownUserHash = []byte("$2a$10$.xQLE6QHam9CsWmVfibOL.53ZpmsEcA9IRg0iIM7bmeegDkx2HW7K")
+
+// checkLogin verifies a username and password, refusing the attempt while
+// the username or the client IP is backing off or locked out. Failures are
+// counted and recorded in failed_logins; attempts refused while backing off
+// are not, so a flood of them cannot fill the table. It writes the error
+// response and returns false if the login is rejected.
+func (h *Handler) checkLogin(c *gin.Context, role string, req LoginRequest, lookup func() (*account, error)) (*account, bool) {
+	userKey := lockout.UserKey(role, strings.ToLower(strings.TrimSpace(req.Username)))
+	ipKey := lockout.IPKey(c.ClientIP())
+	acc, err := lookup()
+	if err != nil && err != store.ErrNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"})
+		return nil, false
+	}
+	fail := func(reason string) {
+		f := models.FailedLogin{Role: role, Username: truncate(req.Username, 100), Reason: reason, ClientIP: c.ClientIP()}
+		if acc != nil {
+			f.UserID, f.OrganisationID = &acc.ID, &acc.OrganisationID
+		}
+		if err := h.store.Logins.RecordFailedLogin(&f); err != nil {
+			log.Printf("Failed to record failed %s login: %v", role, err)
+		}
+	}
+
+	wait, locked, err := h.guard.Wait(userKey, ipKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"})
+		return nil, false
+	}
+	if wait > 0 {
+		seconds := int(math.Ceil(wait.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		msg := "Too many failed login attempts, try again in %d seconds"
+		if locked {
+			msg = "Too many failed login attempts, login is locked for %d seconds"
+		}
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf(msg, seconds), "retryAfter": seconds})
+		return nil, false
+	}
+
+	reason := "unknown_user"
+	if acc == nil {
+		// Spend as long as a real check so response times don't reveal
+		// which usernames exist
+		bcrypt.CompareHashAndPassword(unknownUserHash, []byte(req.Password))
+	} else if bcrypt.CompareHashAndPassword([]byte(acc.PasswordHash), []byte(req.Password)) == nil {
+		if err := h.guard.Succeed(userKey); err != nil {
+			log.Printf("Failed to reset login failures: %v", err)
+		}
+		// Only tell a disabled account apart once the password is right
+		if !acc.Active {
+			fail("disabled")
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+			return nil, false
+		}
+		return acc, true
+	} else {
+		reason = "bad_password"
+	}
+	fail(reason)
+	if err := h.guard.Fail(userKey, ipKey); err != nil {
+		log.Printf("Failed to count failed login: %v", err)
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+	return nil, false
+}
+
+// truncate cuts s to at most n runes
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// RefreshTokens exchanges a refresh token for a new access and refresh token.
+// Each refresh token works once; presenting a used one revokes its session,
+// since it means the token was copied. Tokens of ended sessions are revoked
+// too, so they get the same answer.
+func (h *Handler) RefreshTokens(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	old, err := h.store.Tokens.GetRefreshToken(hashToken(req.RefreshToken))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+	if old.RevokedAt != nil {
+		h.revokeReusedSession(old)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has ended, please log in again"})
+		return
+	}
+	if !old.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token expired, please log in again"})
+		return
+	}
+	switch old.Role {
+	case "admin":
+		_, err = h.store.Admins.GetAdmin(old.UserID)
+	case "worker":
+		var w *models.Worker
+		if w, err = h.store.Workers.GetWorker(old.UserID, old.OrganisationID); err == nil && !w.IsActive {
+			err = store.ErrNotFound
+		}
+	default:
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		h.store.Tokens.RevokeSession(old.SessionID)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Account no longer exists or is disabled"})
+		return
+	}
+	h.issueTokens(c, models.RefreshToken{Role: old.Role, UserID: old.UserID, OrganisationID: old.OrganisationID, SessionID: old.SessionID}, old.ID)
+}
+
+// revokeReusedSession ends the session of a refresh token presented twice
+func (h *Handler) revokeReusedSession(t *models.RefreshToken) {
+	log.Printf("Revoked refresh token presented for %s %d, revoking session %s", t.Role, t.UserID, t.SessionID)
+	if err := h.store.Tokens.RevokeSession(t.SessionID); err != nil {
+		log.Printf("Failed to revoke session %s: %v", t.SessionID, err)
+	}
+}
+
+// Logout ends the session of the access token used for the request
+func (h *Handler) Logout(c *gin.Context) {
+	if err := h.store.Tokens.RevokeSession(c.GetString("session_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// Admin ends every session of one of their workers
+func (h *Handler) RevokeWorkerSessions(c *gin.Context) {
+	workerId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worker ID"})
 		return
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":  worker.ID,
-		"role":     "worker",
-		"admin_id": worker.AdminID,
-		"exp":      time.Now().Add( 30 * 24 * time.Hour ).Unix(),
+	if _, err := h.store.Workers.GetWorker(workerId, c.GetInt("organisation_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worker not found or not authorized"})
+		return
+	}
+	revoked, err := h.store.Tokens.RevokeUserSessions("worker", workerId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sessions revoked", "revokedSessions": revoked})
+}
+
+// Admin lifts a lockout on one of their workers after failed logins
+func (h *Handler) UnlockWorker(c *gin.Context) {
+	workerId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worker ID"})
+		return
+	}
+	w, err := h.store.Workers.GetWorker(workerId, c.GetInt("organisation_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worker not found or not authorized"})
+		return
+	}
+	if err := h.guard.Unlock(lockout.UserKey("worker", strings.ToLower(w.Username))); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock worker"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Worker unlocked"})
+}
+
+// Admin lists failed logins on their own and their workers' accounts
+func (h *Handler) ListFailedLogins(c *gin.Context) {
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	q := store.FailedLoginQuery{Role: c.Query("role"), Username: c.Query("username"), Limit: limit, Offset: offset}
+	logins, total, err := h.store.Logins.ListFailedLogins(c.GetInt("organisation_id"), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch failed logins"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"failedLogins": logins,
+		"total":        total,
+		"limit":        limit,
+		"offset":       offset,
+	})
+}
+
+// issueTokens signs an access token for the session in t and stores its
+// next refresh token. A zero previousID starts a new session; otherwise the
+// refresh token with that id is rotated out.
+func (h *Handler) issueTokens(c *gin.Context, t models.RefreshToken, previousID int) {
+	refresh, err := newOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create session"})
+		return
+	}
+	if t.SessionID == "" {
+		if t.SessionID, err = newSessionID(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create session"})
+			return
+		}
+	}
+	t.TokenHash = hashToken(refresh)
+	t.ExpiresAt = time.Now().Add(h.cfg.Auth.RefreshTokenLifetime)
+	if previousID == 0 {
+		err = h.store.Tokens.CreateRefreshToken(&t)
+	} else {
+		err = h.store.Tokens.RotateRefreshToken(previousID, &t)
+	}
+	if err == store.ErrConflict {
+		// Another request rotated the same token first
+		h.revokeReusedSession(&t)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has ended, please log in again"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create session"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"user_id": t.UserID,
+		"role":    t.Role,
+		"sid":     t.SessionID,
+		"exp":     time.Now().Add(h.cfg.Auth.AccessTokenLifetime).Unix(),
+	}
+	if t.Role == "worker" {
+		claims["org_id"] = t.OrganisationID
+	}
+	tokenString, err := h.keys.Sign(claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create session"})
+		return
+	}
+	c.JSON(http.StatusOK, LoginResponse{
+		Token:        tokenString,
+		RefreshToken: refresh,
+		ExpiresIn:    int(h.cfg.Auth.AccessTokenLifetime.Seconds()),
 	})
-	tokenString, _ := token.SignedString(jwtSecret)
-	c.JSON(http.StatusOK, LoginResponse{Token: tokenString})
+}
+
+// JWKS publishes the public keys access tokens can be verified with
+func (h *Handler) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
+
+// newOpaqueToken returns a random refresh token; only its hash is stored
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Vanaraj10/interior-backend/lifecycle"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/search"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/gin-gonic/gin"
+)
+
+// LinkCustomer links a saved project to the customer with its phone number,
+// creating the customer or updating their details from the project. A
+// project whose phone has no digits is linked to no customer.
+func LinkCustomer(s *store.Store, p *models.Project) error {
+	var customerId *int
+	if phone := search.NormalizePhone(p.Phone); phone != "" {
+		customer := models.Customer{
+			OrganisationID:  p.OrganisationID,
+			Name:            p.ClientName,
+			Phone:           p.Phone,
+			NormalizedPhone: phone,
+			Address:         p.Address,
+		}
+		if err := s.Customers.SaveCustomer(&customer); err != nil {
+			return err
+		}
+		customerId = &customer.ID
+	}
+	if err := s.Customers.SetProjectCustomer(p.ID, customerId); err != nil {
+		return err
+	}
+	p.CustomerID = customerId
+	return nil
+}
+
+// customerQuotation is a project of a customer with every saved revision of
+// its quotation, oldest first
+type customerQuotation struct {
+	Project   models.ProjectSummary    `json:"project"`
+	Revisions []models.ProjectRevision `json:"revisions"`
+}
+
+// Admin gets a customer with their projects, newest first, and the revisions
+// of each project's quotation. lifetimeValue adds up the totals of the
+// projects the customer ordered (approved or later), quotedValue those of
+// every project.
+func (h *Handler) GetCustomer(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	customerId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+	customer, err := h.store.Customers.GetCustomer(customerId, orgId)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch customer"})
+		return
+	}
+
+	q := store.ProjectQuery{CustomerID: &customerId, Sort: store.SortCreatedAt, Desc: true, Limit: maxPageSize}
+	var projects []models.Project
+	for {
+		page, counts, err := h.store.Projects.ListProjectsByOrganisation(orgId, q)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+			return
+		}
+		projects = append(projects, page...)
+		q.Offset += len(page)
+		if len(page) == 0 || q.Offset >= counts.Total {
+			break
+		}
+	}
+
+	quotations := make([]customerQuotation, len(projects))
+	var lifetimeValue, quotedValue float64
+	for i := range projects {
+		summary := projectSummary(&projects[i])
+		if summary.Total != nil {
+			quotedValue += *summary.Total
+			if lifecycle.Ordered(summary.Status) {
+				lifetimeValue += *summary.Total
+			}
+		}
+		revisions, err := h.store.Revisions.ListRevisions(summary.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+			return
+		}
+		for j := range revisions {
+			revisionTotal(&revisions[j])
+			revisions[j].HTML = ""
+			revisions[j].RawData = ""
+		}
+		quotations[i] = customerQuotation{Project: summary, Revisions: revisions}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"customer":      customer,
+		"projectCount":  len(projects),
+		"lifetimeValue": lifetimeValue,
+		"quotedValue":   quotedValue,
+		"quotations":    quotations,
+	})
+}
+
+// Admin merges a duplicate customer into this one: the duplicate's projects
+// move over, and projects later saved with its phone number join this
+// customer too
+func (h *Handler) MergeCustomer(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	customerId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+	var req struct {
+		CustomerID int `json:"customerId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "customerId of the duplicate is required"})
+		return
+	}
+	if req.CustomerID == customerId {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot merge a customer into itself"})
+		return
+	}
+	if err := h.store.Customers.MergeCustomers(orgId, customerId, req.CustomerID); err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge customers"})
+		return
+	}
+	customer, err := h.store.Customers.GetCustomer(customerId, orgId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch customer"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Customers merged", "customer": customer, "mergedId": req.CustomerID})
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"github.com/Vanaraj10/interior-backend/auth"
+	"github.com/Vanaraj10/interior-backend/config"
+	"github.com/Vanaraj10/interior-backend/lockout"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+// Handler serves the HTTP API on top of the configured storage backend
+type Handler struct {
+	cfg   *config.Config
+	store *store.Store
+	keys  *auth.Keys
+	guard *lockout.Guard
+}
+
+// New creates a Handler using the given configuration, store, token signing
+// keys and login guard
+func New(cfg *config.Config, s *store.Store, keys *auth.Keys, guard *lockout.Guard) *Handler {
+	return &Handler{cfg: cfg, store: s, keys: keys, guard: guard}
+}
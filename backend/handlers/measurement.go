@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProjectMeasurements returns the rooms and measurements normalized from a project's rawData
+func (h *Handler) GetProjectMeasurements(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	if _, err := h.store.Projects.GetProject(projectId, orgId); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	rooms, measurements, err := h.store.Measurements.ListProjectMeasurements(projectId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch measurements"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"projectId":    projectId,
+		"rooms":        rooms,
+		"measurements": measurements,
+	})
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Vanaraj10/interior-backend/access"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Admin fetches their own account, role and permissions
+func (h *Handler) GetCurrentAdmin(c *gin.Context) {
+	admin, err := h.store.Admins.GetAdmin(c.GetInt("admin_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch admin"})
+		return
+	}
+	permissions := access.Grants[admin.Role]
+	if permissions == nil {
+		permissions = []access.Permission{}
+	}
+	c.JSON(http.StatusOK, gin.H{"admin": admin, "permissions": permissions})
+}
+
+// Admin fetches their organisation and its admins
+func (h *Handler) GetOrganisation(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	org, err := h.store.Admins.GetOrganisation(orgId)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organisation not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch organisation"})
+		return
+	}
+	admins, err := h.store.Admins.ListAdmins(orgId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch admins"})
+		return
+	}
+	if admins == nil {
+		admins = []models.Admin{}
+	}
+	c.JSON(http.StatusOK, gin.H{"organisation": org, "admins": admins})
+}
+
+// Owner renames their organisation
+func (h *Handler) UpdateOrganisation(c *gin.Context) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+	if err := h.store.Admins.UpdateOrganisation(c.GetInt("organisation_id"), req.Name); err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organisation not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update organisation"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Organisation updated"})
+}
+
+// Owner adds an admin to their organisation
+func (h *Handler) CreateAdmin(c *gin.Context) {
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Role     string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
+		return
+	}
+	if !access.ValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role", "roles": access.Roles})
+		return
+	}
+	if err := h.checkPassword(req.Password, req.Username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hash, _ := HashPassword(req.Password)
+	admin := models.Admin{Username: req.Username, PasswordHash: hash, OrganisationID: c.GetInt("organisation_id"), Role: req.Role}
+	if err := h.store.Admins.CreateAdmin(&admin); err == store.ErrConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create admin"})
+		return
+	}
+	c.Set("audit_entity_id", admin.ID)
+	c.JSON(http.StatusCreated, admin)
+}
+
+// Owner changes the role of an admin in their organisation
+func (h *Handler) UpdateAdminRole(c *gin.Context) {
+	adminId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid admin ID"})
+		return
+	}
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if !access.ValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role", "roles": access.Roles})
+		return
+	}
+	orgId := c.GetInt("organisation_id")
+	if req.Role != access.Owner {
+		if ok := h.keepsAnOwner(c, adminId, orgId); !ok {
+			return
+		}
+	}
+	if err := h.store.Admins.UpdateAdminRole(adminId, orgId, req.Role); err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update admin"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Admin updated"})
+}
+
+// Owner removes an admin from their organisation and ends their sessions
+func (h *Handler) DeleteAdmin(c *gin.Context) {
+	adminId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid admin ID"})
+		return
+	}
+	if adminId == c.GetInt("admin_id") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot delete your own account"})
+		return
+	}
+	orgId := c.GetInt("organisation_id")
+	if ok := h.keepsAnOwner(c, adminId, orgId); !ok {
+		return
+	}
+	if err := h.store.Admins.DeleteAdmin(adminId, orgId); err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete admin"})
+		return
+	}
+	if _, err := h.store.Tokens.RevokeUserSessions("admin", adminId); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end admin sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Admin deleted"})
+}
+
+// keepsAnOwner responds with an error and returns false if adminId is the
+// organisation's last owner, who can be neither demoted nor deleted
+func (h *Handler) keepsAnOwner(c *gin.Context, adminId, orgId int) bool {
+	admins, err := h.store.Admins.ListAdmins(orgId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch admins"})
+		return false
+	}
+	owners, isOwner := 0, false
+	for _, a := range admins {
+		if a.Role == access.Owner {
+			owners++
+			isOwner = isOwner || a.ID == adminId
+		}
+	}
+	if isOwner && owners == 1 {
+		c.JSON(http.StatusConflict, gin.H{"error": "An organisation must keep at least one owner"})
+		return false
+	}
+	return true
+}
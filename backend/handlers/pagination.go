@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// parsePage reads the limit and offset query parameters, applying the default
+// page size and capping limit at maxPageSize
+func parsePage(c *gin.Context) (limit, offset int, err error) {
+	limit = defaultPageSize
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return 0, 0, errors.New("Invalid limit")
+		}
+		if limit > maxPageSize {
+			limit = maxPageSize
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("Invalid offset")
+		}
+	}
+	return limit, offset, nil
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// checkPassword applies the password policy to a new password for the given
+// username
+func (h *Handler) checkPassword(password, username string) error {
+	return CheckPassword(password, username, h.cfg.Auth.PasswordMinLength)
+}
+
+// CheckPassword applies the password policy: at least minLength characters
+// and at most 72 bytes, with a letter and a digit, and not containing the
+// username.
+func CheckPassword(password, username string, minLength int) error {
+	if n := len([]rune(password)); n < minLength {
+		return fmt.Errorf("Password must be at least %d characters", minLength)
+	}
+	// bcrypt ignores everything after 72 bytes
+	if len(password) > 72 {
+		return errors.New("Password must be at most 72 bytes")
+	}
+	var letter, digit bool
+	for _, r := range password {
+		letter = letter || unicode.IsLetter(r)
+		digit = digit || unicode.IsDigit(r)
+	}
+	if !letter || !digit {
+		return errors.New("Password must contain a letter and a digit")
+	}
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return errors.New("Password must not contain the username")
+	}
+	return nil
+}
+
+// temporaryPasswordChars leaves out characters that are easily misread
+const temporaryPasswordChars = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// newTemporaryPassword returns a random password that passes the policy
+func (h *Handler) newTemporaryPassword(username string) (string, error) {
+	n := h.cfg.Auth.PasswordMinLength
+	if n < 12 {
+		n = 12
+	}
+	for {
+		b := make([]byte, n)
+		for i := range b {
+			j, err := rand.Int(rand.Reader, big.NewInt(int64(len(temporaryPasswordChars))))
+			if err != nil {
+				return "", err
+			}
+			b[i] = temporaryPasswordChars[j.Int64()]
+		}
+		if h.checkPassword(string(b), username) == nil {
+			return string(b), nil
+		}
+	}
+}
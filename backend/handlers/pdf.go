@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Vanaraj10/interior-backend/pdf"
+	"github.com/Vanaraj10/interior-backend/pricing"
+	"github.com/Vanaraj10/interior-backend/templates"
+	"github.com/gin-gonic/gin"
+)
+
+// GetQuotationPDF renders the customer quotation and stitching sheet from rawData.
+// ?document=quotation or ?document=stitching limits the PDF to one of them.
+func (h *Handler) GetQuotationPDF(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	opts := pdf.Options{}
+	switch c.Query("document") {
+	case "":
+		opts.Quotation, opts.Stitching = true, true
+	case "quotation":
+		opts.Quotation = true
+	case "stitching":
+		opts.Stitching = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "document must be quotation or stitching"})
+		return
+	}
+
+	p, err := h.store.Projects.GetProject(projectId, orgId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	if p.RawData == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No raw data found for this project"})
+		return
+	}
+	quotation, err := pricing.QuoteProject(p.RawData)
+	if err != nil {
+		rawDataError(c, err)
+		return
+	}
+	if !opts.Quotation && len(quotation.Curtains.Rooms) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This project does not contain curtain measurements"})
+		return
+	}
+
+	settings, err := h.quotationTemplate(orgId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load quotation template"})
+		return
+	}
+	branding := templates.BrandingFor(settings)
+	opts.Company = pdf.DefaultCompany
+	if branding.CompanyName != "" {
+		opts.Company = pdf.Company{Name: branding.CompanyName}
+	}
+	opts.Notes = branding.Notes
+
+	var buf bytes.Buffer
+	if err := pdf.Render(&buf, *p, quotation, opts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render PDF"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="quotation-%d.pdf"`, p.ID))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Vanaraj10/interior-backend/pricing"
+	"github.com/Vanaraj10/interior-backend/projectdata"
+	"github.com/gin-gonic/gin"
+)
+
+// GetProjectPricing recomputes the curtain quotation from rawData and flags mismatches
+func (h *Handler) GetProjectPricing(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	p, err := h.store.Projects.GetProject(projectId, orgId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	if p.RawData == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No raw data found for this project"})
+		return
+	}
+
+	quotation, err := pricing.QuoteRawData(p.RawData)
+	if err != nil {
+		rawDataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"projectId":  p.ID,
+		"clientName": p.ClientName,
+		"pricing":    quotation,
+	})
+}
+
+// rawDataError reports a project whose rawData cannot be read, listing the invalid fields
+func rawDataError(c *gin.Context, err error) {
+	var fields projectdata.ValidationErrors
+	if errors.As(err, &fields) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project data", "fields": fields})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
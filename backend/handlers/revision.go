@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/pricing"
+	"github.com/gin-gonic/gin"
+)
+
+// revisionTotal sets the quotation grand total of a revision, if its rawData can be priced
+func revisionTotal(r *models.ProjectRevision) {
+	if r.RawData == "" {
+		return
+	}
+	if q, err := pricing.QuoteProject(r.RawData); err == nil {
+		r.Total = &q.GrandTotal
+	}
+}
+
+// Admin lists the saved revisions of a project, without their html and rawData
+func (h *Handler) ListProjectRevisions(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	if _, err := h.store.Projects.GetProject(projectId, orgId); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	revisions, err := h.store.Revisions.ListRevisions(projectId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+	for i := range revisions {
+		revisionTotal(&revisions[i])
+		revisions[i].HTML = ""
+		revisions[i].RawData = ""
+	}
+	c.JSON(http.StatusOK, gin.H{"projectId": projectId, "revisions": revisions})
+}
+
+// Admin gets one revision of a project with its full HTML and rawData
+func (h *Handler) GetProjectRevision(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision"})
+		return
+	}
+	if _, err := h.store.Projects.GetProject(projectId, orgId); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	r, err := h.store.Revisions.GetRevision(projectId, revision)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+	revisionTotal(r)
+	if reconstructedHTML, err := reconstructProjectHTML(r.HTML); err == nil {
+		r.HTML = reconstructedHTML
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+// Admin compares the measurements of two revisions of a project. to defaults
+// to the latest revision and from to the one before it.
+func (h *Handler) DiffProjectRevisions(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	if _, err := h.store.Projects.GetProject(projectId, orgId); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	revisions, err := h.store.Revisions.ListRevisions(projectId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+	to := len(revisions)
+	if v := c.Query("to"); v != "" {
+		if to, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to revision"})
+			return
+		}
+	}
+	from := to - 1
+	if v := c.Query("from"); v != "" {
+		if from, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from revision"})
+			return
+		}
+	}
+	// Revisions are numbered from 1 with no gaps
+	if from < 1 || from > len(revisions) || to < 1 || to > len(revisions) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+	diff, err := pricing.DiffRawData(revisions[from-1].RawData, revisions[to-1].RawData)
+	if err != nil {
+		rawDataError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"projectId": projectId,
+		"from":      from,
+		"to":        to,
+		"diff":      diff,
+	})
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Vanaraj10/interior-backend/projectdata"
+	"github.com/gin-gonic/gin"
+)
+
+// ListSchemas returns the interior-type schemas submitted measurements are validated against
+func (h *Handler) ListSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version": projectdata.CurrentVersion,
+		"schemas": projectdata.Schemas,
+	})
+}
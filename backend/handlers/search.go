@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/search"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchResults = 20
+	maxSearchResults     = 50
+)
+
+// Admin searches their projects by client name, phone, address and the
+// rooms and materials in rawData, best matches first. ?limit= caps the
+// results.
+func (h *Handler) Search(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	query := c.Query("q")
+	terms := search.Query(query)
+	if len(terms) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		return
+	}
+	limit := defaultSearchResults
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = min(n, maxSearchResults)
+	}
+	hits, err := h.store.Search.SearchProjects(orgId, terms, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search projects"})
+		return
+	}
+	ids := make([]int, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ProjectID
+	}
+	projects, err := h.store.Projects.ListProjectsByID(ids, orgId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		return
+	}
+	byId := make(map[int]*models.Project, len(projects))
+	for i := range projects {
+		byId[projects[i].ID] = &projects[i]
+	}
+	results := make([]gin.H, 0, len(hits))
+	for _, hit := range hits {
+		p, ok := byId[hit.ProjectID]
+		if !ok {
+			// Deleted since the search ran
+			continue
+		}
+		results = append(results, gin.H{"score": hit.Score, "project": projectSummary(p)})
+	}
+	c.JSON(http.StatusOK, gin.H{"query": query, "terms": terms, "results": results})
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/access"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/gin-gonic/gin"
+)
+
+// organisationRequest is the body shared by setup and signup: a new
+// organisation and the username and password of its owner
+type organisationRequest struct {
+	Organisation string `json:"organisation"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	Token        string `json:"token"`
+	InviteCode   string `json:"invite_code"`
+}
+
+// newOrganisation checks the request and returns the organisation and owner
+// to create. It responds with an error and returns false if the request is
+// invalid.
+func (h *Handler) newOrganisation(c *gin.Context, req *organisationRequest) (*models.Organisation, *models.Admin, bool) {
+	req.Organisation = strings.TrimSpace(req.Organisation)
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Organisation == "" || req.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Organisation and username are required"})
+		return nil, nil, false
+	}
+	if err := h.checkPassword(req.Password, req.Username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	hash, err := HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return nil, nil, false
+	}
+	org := &models.Organisation{Name: req.Organisation}
+	owner := &models.Admin{Username: req.Username, PasswordHash: hash, Role: access.Owner}
+	return org, owner, true
+}
+
+// GetSetupStatus reports whether first-run setup can be used: it is enabled
+// and no admin exists yet
+func (h *Handler) GetSetupStatus(c *gin.Context) {
+	if !h.cfg.Setup.Enabled {
+		c.JSON(http.StatusOK, gin.H{"available": false})
+		return
+	}
+	count, err := h.store.Admins.CountAdmins()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check admins"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"available": count == 0, "tokenRequired": h.cfg.Setup.Token != ""})
+}
+
+// Setup creates the first organisation and its owner on a new installation
+func (h *Handler) Setup(c *gin.Context) {
+	if !h.cfg.Setup.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Setup is not enabled"})
+		return
+	}
+	var req organisationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(req.Token), []byte(h.cfg.Setup.Token)) != 1 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid setup token"})
+		return
+	}
+	org, owner, ok := h.newOrganisation(c, &req)
+	if !ok {
+		return
+	}
+	if err := h.store.Admins.CreateFirstOrganisation(org, owner); err == store.ErrConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": "Setup has already been completed"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create organisation"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"organisation": org, "admin": owner})
+}
+
+// Signup creates a new organisation and its owner with an invite code
+func (h *Handler) Signup(c *gin.Context) {
+	var req organisationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if req.InviteCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invite code is required"})
+		return
+	}
+	org, owner, ok := h.newOrganisation(c, &req)
+	if !ok {
+		return
+	}
+	if err := h.store.Admins.RedeemInvite(hashToken(req.InviteCode), org, owner); err == store.ErrNotFound {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invite code is invalid, used or expired"})
+		return
+	} else if err == store.ErrConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create organisation"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"organisation": org, "admin": owner})
+}
+
+// NewInvite returns a new signup invite and its code. Only the invite, which
+// holds a hash of the code, is meant to be stored.
+func NewInvite(note string, lifetime time.Duration) (*models.Invite, string, error) {
+	code, err := newOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	return &models.Invite{CodeHash: hashToken(code), Note: note, ExpiresAt: time.Now().Add(lifetime)}, code, nil
+}
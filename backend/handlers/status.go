@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Vanaraj10/interior-backend/lifecycle"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/gin-gonic/gin"
+)
+
+// ListProjectStatuses returns the project statuses and the transition table
+func (h *Handler) ListProjectStatuses(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"statuses":    lifecycle.Statuses,
+		"transitions": lifecycle.Transitions,
+	})
+}
+
+// Admin moves a project to another status
+func (h *Handler) UpdateProjectStatus(c *gin.Context) {
+	adminId := c.GetInt("admin_id")
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	p, err := h.store.Projects.GetProject(projectId, orgId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	h.changeStatus(c, p, lifecycle.RoleAdmin, adminId)
+}
+
+// Worker moves one of their projects to another status
+func (h *Handler) WorkerUpdateProjectStatus(c *gin.Context) {
+	workerId := c.GetInt("worker_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	p, err := h.store.Projects.GetWorkerProject(projectId, workerId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	h.changeStatus(c, p, lifecycle.RoleWorker, workerId)
+}
+
+// changeStatus reads a requested status change and applies it with moveProject
+func (h *Handler) changeStatus(c *gin.Context, p *models.Project, role string, actorID int) {
+	var req struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(req.Note) > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Note must be at most 500 characters"})
+		return
+	}
+	h.moveProject(c, p, req.Status, req.Note, role, actorID)
+}
+
+// Admin marks a project completed or not. This is kept for older clients:
+// completing moves the project to installed, and nothing leaves a completed
+// status, so isCompleted always follows the status.
+func (h *Handler) ToggleProjectCompleted(c *gin.Context) {
+	adminId := c.GetInt("admin_id")
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	p, err := h.store.Projects.GetProject(projectId, orgId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	h.setCompleted(c, p, lifecycle.RoleAdmin, adminId)
+}
+
+// Worker marks one of their projects completed or not, as ToggleProjectCompleted
+func (h *Handler) WorkerToggleProjectCompleted(c *gin.Context) {
+	workerId := c.GetInt("worker_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	p, err := h.store.Projects.GetWorkerProject(projectId, workerId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	h.setCompleted(c, p, lifecycle.RoleWorker, workerId)
+}
+
+// setCompleted turns an isCompleted toggle into the matching status change
+func (h *Handler) setCompleted(c *gin.Context, p *models.Project, role string, actorID int) {
+	var req struct {
+		IsCompleted bool `json:"isCompleted"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if lifecycle.Completed(p.Status) == req.IsCompleted {
+		c.JSON(http.StatusOK, gin.H{"status": p.Status, "next": lifecycle.Next(p.Status, role)})
+		return
+	}
+	if !req.IsCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Project is %s and cannot be reopened", p.Status)})
+		return
+	}
+	h.moveProject(c, p, lifecycle.Installed, "", role, actorID)
+}
+
+// moveProject checks a transition against the transition table and records
+// it for the given actor
+func (h *Handler) moveProject(c *gin.Context, p *models.Project, to, note, role string, actorID int) {
+	switch err := lifecycle.Check(p.Status, to, role); err {
+	case nil:
+	case lifecycle.ErrUnknownStatus:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status", "statuses": lifecycle.Statuses})
+		return
+	case lifecycle.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("A %s cannot move a project from %s to %s", role, p.Status, to)})
+		return
+	default:
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("Cannot move a project from %s to %s", p.Status, to),
+			"next":  lifecycle.Next(p.Status, role),
+		})
+		return
+	}
+
+	t := models.StatusTransition{
+		ProjectID:  p.ID,
+		FromStatus: p.Status,
+		ToStatus:   to,
+		ActorRole:  role,
+		ActorID:    actorID,
+		Note:       note,
+	}
+	err := h.store.Projects.SetProjectStatus(&t)
+	if err == store.ErrConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": "Project status was changed by someone else, reload and try again"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update project status"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":     t.ToStatus,
+		"next":       lifecycle.Next(t.ToStatus, role),
+		"transition": t,
+	})
+}
+
+// Admin gets a project's status history
+func (h *Handler) GetProjectStatusHistory(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	projectId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	p, err := h.store.Projects.GetProject(projectId, orgId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+		return
+	}
+	transitions, err := h.store.Projects.ListStatusTransitions(p.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch status history"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"projectId":   p.ID,
+		"status":      p.Status,
+		"next":        lifecycle.Next(p.Status, lifecycle.RoleAdmin),
+		"transitions": transitions,
+	})
+}
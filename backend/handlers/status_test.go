@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Vanaraj10/interior-backend/lifecycle"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store/memory"
+	"github.com/gin-gonic/gin"
+)
+
+// TestToggleProjectCompleted sends the legacy isCompleted toggle, both ways
+// and as either role, to a project in each status
+func TestToggleProjectCompleted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := memory.New()
+	h := New(nil, s, nil, nil)
+	o := &models.Organisation{Name: "Acme"}
+	owner := &models.Admin{Username: "owner", PasswordHash: "hash", Role: "owner"}
+	if err := s.Admins.CreateOrganisation(o, owner); err != nil {
+		t.Fatal(err)
+	}
+	w := &models.Worker{Username: "ravi", PasswordHash: "hash", OrganisationID: o.ID, IsActive: true}
+	if err := s.Workers.CreateWorker(w); err != nil {
+		t.Fatal(err)
+	}
+
+	// newProjectIn walks a new project along the statuses, which are in
+	// transition order, until it reaches status
+	newProjectIn := func(status string) *models.Project {
+		p := &models.Project{ClientName: "Anitha", WorkerID: w.ID, OrganisationID: o.ID}
+		if err := s.Projects.CreateProject(p); err != nil {
+			t.Fatal(err)
+		}
+		for _, next := range lifecycle.Statuses[1:] {
+			if p.Status == status {
+				break
+			}
+			tr := &models.StatusTransition{ProjectID: p.ID, FromStatus: p.Status, ToStatus: next, ActorRole: lifecycle.RoleAdmin, ActorID: owner.ID}
+			if err := s.Projects.SetProjectStatus(tr); err != nil {
+				t.Fatal(err)
+			}
+			p.Status = next
+		}
+		return p
+	}
+
+	tests := []struct {
+		from        string
+		isCompleted bool
+		role        string
+		code        int
+		to          string
+	}{
+		{lifecycle.Measured, true, lifecycle.RoleAdmin, http.StatusConflict, lifecycle.Measured},
+		{lifecycle.Quoted, true, lifecycle.RoleAdmin, http.StatusConflict, lifecycle.Quoted},
+		{lifecycle.Approved, true, lifecycle.RoleAdmin, http.StatusConflict, lifecycle.Approved},
+		{lifecycle.AdvancePaid, true, lifecycle.RoleAdmin, http.StatusConflict, lifecycle.AdvancePaid},
+		{lifecycle.InStitching, true, lifecycle.RoleAdmin, http.StatusOK, lifecycle.Installed},
+		{lifecycle.Installed, true, lifecycle.RoleAdmin, http.StatusOK, lifecycle.Installed},
+		{lifecycle.Closed, true, lifecycle.RoleAdmin, http.StatusOK, lifecycle.Closed},
+		{lifecycle.Measured, false, lifecycle.RoleAdmin, http.StatusOK, lifecycle.Measured},
+		{lifecycle.Quoted, false, lifecycle.RoleAdmin, http.StatusOK, lifecycle.Quoted},
+		{lifecycle.Approved, false, lifecycle.RoleAdmin, http.StatusOK, lifecycle.Approved},
+		{lifecycle.AdvancePaid, false, lifecycle.RoleAdmin, http.StatusOK, lifecycle.AdvancePaid},
+		{lifecycle.InStitching, false, lifecycle.RoleAdmin, http.StatusOK, lifecycle.InStitching},
+		{lifecycle.Installed, false, lifecycle.RoleAdmin, http.StatusConflict, lifecycle.Installed},
+		{lifecycle.Closed, false, lifecycle.RoleAdmin, http.StatusConflict, lifecycle.Closed},
+		{lifecycle.Measured, true, lifecycle.RoleWorker, http.StatusConflict, lifecycle.Measured},
+		{lifecycle.Quoted, true, lifecycle.RoleWorker, http.StatusConflict, lifecycle.Quoted},
+		{lifecycle.Approved, true, lifecycle.RoleWorker, http.StatusConflict, lifecycle.Approved},
+		{lifecycle.AdvancePaid, true, lifecycle.RoleWorker, http.StatusConflict, lifecycle.AdvancePaid},
+		{lifecycle.InStitching, true, lifecycle.RoleWorker, http.StatusOK, lifecycle.Installed},
+		{lifecycle.Installed, true, lifecycle.RoleWorker, http.StatusOK, lifecycle.Installed},
+		{lifecycle.Closed, true, lifecycle.RoleWorker, http.StatusOK, lifecycle.Closed},
+		{lifecycle.Measured, false, lifecycle.RoleWorker, http.StatusOK, lifecycle.Measured},
+		{lifecycle.Quoted, false, lifecycle.RoleWorker, http.StatusOK, lifecycle.Quoted},
+		{lifecycle.Approved, false, lifecycle.RoleWorker, http.StatusOK, lifecycle.Approved},
+		{lifecycle.AdvancePaid, false, lifecycle.RoleWorker, http.StatusOK, lifecycle.AdvancePaid},
+		{lifecycle.InStitching, false, lifecycle.RoleWorker, http.StatusOK, lifecycle.InStitching},
+		{lifecycle.Installed, false, lifecycle.RoleWorker, http.StatusConflict, lifecycle.Installed},
+		{lifecycle.Closed, false, lifecycle.RoleWorker, http.StatusConflict, lifecycle.Closed},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s %s isCompleted=%v", tt.role, tt.from, tt.isCompleted)
+		p := newProjectIn(tt.from)
+
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(fmt.Sprintf(`{"isCompleted":%v}`, tt.isCompleted)))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: strconv.Itoa(p.ID)}}
+		if tt.role == lifecycle.RoleAdmin {
+			c.Set("admin_id", owner.ID)
+			c.Set("organisation_id", o.ID)
+			h.ToggleProjectCompleted(c)
+		} else {
+			c.Set("worker_id", w.ID)
+			h.WorkerToggleProjectCompleted(c)
+		}
+
+		if rec.Code != tt.code {
+			t.Errorf("%s: got status code %d, want %d: %s", name, rec.Code, tt.code, rec.Body)
+			continue
+		}
+		if tt.code == http.StatusOK {
+			var resp struct {
+				Status string `json:"status"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Status != tt.to {
+				t.Errorf("%s: response status %q, %v, want %q", name, resp.Status, err, tt.to)
+			}
+		}
+		got, err := s.Projects.GetProject(p.ID, o.ID)
+		if err != nil || got.Status != tt.to || got.IsCompleted != lifecycle.Completed(tt.to) {
+			t.Errorf("%s: project = %+v, %v, want status %s", name, got, err, tt.to)
+		}
+	}
+}
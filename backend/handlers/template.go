@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/pricing"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/Vanaraj10/interior-backend/templates"
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateRequest replaces an admin's stitching sheet settings. Omitted notes
+// keep the default notes; an empty stitchingTemplate uses the built-in template.
+type TemplateRequest struct {
+	CompanyName       string   `json:"companyName"`
+	LogoURL           string   `json:"logoUrl"`
+	Notes             []string `json:"notes"`
+	StitchingTemplate string   `json:"stitchingTemplate"`
+}
+
+// quotationTemplate returns the admin's saved settings, or nil when they use the defaults
+func (h *Handler) quotationTemplate(orgId int) (*models.QuotationTemplate, error) {
+	t, err := h.store.Templates.GetQuotationTemplate(orgId)
+	if err == store.ErrNotFound {
+		return nil, nil
+	}
+	return t, err
+}
+
+// bindTemplateRequest reads and validates a TemplateRequest, writing a 400 on failure
+func bindTemplateRequest(c *gin.Context, orgId int) (*models.QuotationTemplate, bool) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return nil, false
+	}
+	if req.LogoURL != "" {
+		u, err := url.Parse(req.LogoURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "logoUrl must be an http or https URL"})
+			return nil, false
+		}
+	}
+	t := &models.QuotationTemplate{
+		OrganisationID:    orgId,
+		CompanyName:       req.CompanyName,
+		LogoURL:           req.LogoURL,
+		Notes:             req.Notes,
+		StitchingTemplate: req.StitchingTemplate,
+	}
+	if t.StitchingTemplate != "" {
+		if err := templates.Validate(t.StitchingTemplate, templates.BrandingFor(t)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+	}
+	return t, true
+}
+
+// Admin gets their stitching sheet settings; the built-in template source is
+// returned when no custom template is set, as a starting point for editing
+func (h *Handler) GetStitchingTemplate(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	settings, err := h.quotationTemplate(orgId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load quotation template"})
+		return
+	}
+	branding := templates.BrandingFor(settings)
+	resp := gin.H{
+		"companyName":       branding.CompanyName,
+		"logoUrl":           branding.LogoURL,
+		"notes":             branding.Notes,
+		"stitchingTemplate": templates.DefaultStitchingTemplate(),
+		"isCustomTemplate":  false,
+		"updatedAt":         nil,
+	}
+	if settings != nil {
+		resp["updatedAt"] = settings.UpdatedAt
+		if settings.StitchingTemplate != "" {
+			resp["stitchingTemplate"] = settings.StitchingTemplate
+			resp["isCustomTemplate"] = true
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Admin saves their branding and, optionally, a custom stitching template
+func (h *Handler) UpdateStitchingTemplate(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	t, ok := bindTemplateRequest(c, orgId)
+	if !ok {
+		return
+	}
+	if err := h.store.Templates.SaveQuotationTemplate(t); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save quotation template"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Template saved successfully", "updatedAt": t.UpdatedAt})
+}
+
+// Admin reverts to the built-in template and default branding
+func (h *Handler) ResetStitchingTemplate(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	err := h.store.Templates.DeleteQuotationTemplate(orgId)
+	if err != nil && err != store.ErrNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset quotation template"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Template reset to default"})
+}
+
+// Admin previews unsaved settings as HTML, against ?projectId= or a sample project
+func (h *Handler) PreviewStitchingTemplate(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	t, ok := bindTemplateRequest(c, orgId)
+	if !ok {
+		return
+	}
+	sheet := templates.SampleSheet(templates.BrandingFor(t))
+	if v := c.Query("projectId"); v != "" {
+		projectId, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+		p, err := h.store.Projects.GetProject(projectId, orgId)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not authorized"})
+			return
+		}
+		quotation, err := pricing.QuoteRawData(p.RawData)
+		if err != nil {
+			rawDataError(c, err)
+			return
+		}
+		sheet.Project, sheet.Rooms = *p, quotation.Rooms
+	}
+	html, err := templates.RenderStitching(t.StitchingTemplate, sheet)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+}
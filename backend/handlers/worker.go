@@ -1,16 +1,20 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"github.com/Vanaraj10/interior-backend/config"
+	"github.com/Vanaraj10/interior-backend/lockout"
 	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // Admin creates a worker
-func CreateWorker(c *gin.Context) {
+func (h *Handler) CreateWorker(c *gin.Context) {
 	var req struct {
 		Username string `json:"username"`
 		Password string `json:"password"`
@@ -21,65 +25,258 @@ func CreateWorker(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
-	adminId := c.GetInt("admin_id")
-	db := config.GetDB()
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
+		return
+	}
+	if err := h.checkPassword(req.Password, req.Username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	orgId := c.GetInt("organisation_id")
 	// Check if username exists
-	var count int
-	err := db.QueryRow(`SELECT COUNT(*) FROM workers WHERE username = @p1`, req.Username).Scan(&count)
+	exists, err := h.store.Workers.WorkerUsernameExists(req.Username)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check username"})
 		return
 	}
-	if count > 0 {
+	if exists {
 		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
 		return
 	}
 	// Hash password
 	hash, _ := HashPassword(req.Password)
-	_, err = db.Exec(`INSERT INTO workers (username, password_hash, admin_id, name, phone, created_at) VALUES (@p1, @p2, @p3, @p4, @p5, GETDATE())`,
-		req.Username, hash, adminId, req.Name, req.Phone)
-	if err != nil {
+	worker := models.Worker{Username: req.Username, PasswordHash: hash, OrganisationID: orgId, Name: req.Name, Phone: req.Phone, IsActive: true}
+	if err := h.store.Workers.CreateWorker(&worker); err == store.ErrConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create worker"})
 		return
 	}
+	c.Set("audit_entity_id", worker.ID)
 	c.JSON(http.StatusCreated, gin.H{"message": "Worker created"})
 }
 
-// Admin deletes a worker
-func DeleteWorker(c *gin.Context) {
-	workerId := c.Param("id")
-	adminId := c.GetInt("admin_id")
-	db := config.GetDB()
-	res, err := db.Exec(`DELETE FROM workers WHERE id = @p1 AND admin_id = @p2`, workerId, adminId)
-	n, _ := res.RowsAffected()
+// Admin deletes a worker. With ?reassignTo=<worker id> the worker's projects
+// are moved to that worker first; otherwise they stay with the deleted worker.
+func (h *Handler) DeleteWorker(c *gin.Context) {
+	workerId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worker ID"})
+		return
+	}
+	orgId := c.GetInt("organisation_id")
+	if _, err := h.store.Workers.GetWorker(workerId, orgId); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worker not found or not authorized"})
+		return
+	}
+	reassigned := 0
+	if v := c.Query("reassignTo"); v != "" {
+		toId, err := strconv.Atoi(v)
+		if err != nil || toId == workerId {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reassignTo worker ID"})
+			return
+		}
+		if _, err := h.store.Workers.GetWorker(toId, orgId); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Worker to reassign projects to not found"})
+			return
+		}
+		if reassigned, err = h.store.Projects.ReassignProjects(workerId, toId, orgId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reassign projects"})
+			return
+		}
+	}
+	err = h.store.Workers.DeleteWorker(workerId, orgId)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worker not found or not authorized"})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete worker"})
 		return
 	}
-	if n == 0 {
+	if _, err := h.store.Tokens.RevokeUserSessions("worker", workerId); err != nil {
+		log.Printf("Failed to revoke sessions of deleted worker %d: %v", workerId, err)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Worker deleted", "reassignedProjects": reassigned})
+}
+
+// Admin edits a worker's username, name, phone or active flag. Deactivating
+// a worker ends their sessions and blocks login until they are reactivated.
+func (h *Handler) UpdateWorker(c *gin.Context) {
+	workerId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worker ID"})
+		return
+	}
+	var req struct {
+		Username *string `json:"username"`
+		Name     *string `json:"name"`
+		Phone    *string `json:"phone"`
+		IsActive *bool   `json:"isActive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	update := store.WorkerUpdate{Username: req.Username, Name: req.Name, Phone: req.Phone, IsActive: req.IsActive}
+	if update == (store.WorkerUpdate{}) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No updates provided"})
+		return
+	}
+	if update.Username != nil {
+		username := strings.TrimSpace(*update.Username)
+		if username == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Username must not be empty"})
+			return
+		}
+		update.Username = &username
+	}
+	orgId := c.GetInt("organisation_id")
+	err = h.store.Workers.UpdateWorker(workerId, orgId, update)
+	if err == store.ErrNotFound {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Worker not found or not authorized"})
 		return
+	} else if err == store.ErrConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update worker"})
+		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Worker deleted"})
+	if update.IsActive != nil && !*update.IsActive {
+		if _, err := h.store.Tokens.RevokeUserSessions("worker", workerId); err != nil {
+			log.Printf("Failed to revoke sessions of deactivated worker %d: %v", workerId, err)
+		}
+	}
+	worker, err := h.store.Workers.GetWorker(workerId, orgId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch worker"})
+		return
+	}
+	c.JSON(http.StatusOK, worker)
 }
 
-// Admin lists all workers
-func ListWorkers(c *gin.Context) {
-	adminId := c.GetInt("admin_id")
-	db := config.GetDB()
-	rows, err := db.Query(`SELECT id, username, password_hash, admin_id, name, phone, created_at FROM workers WHERE admin_id = @p1`, adminId)
+// Admin sets a new password for a worker. Without new_password a temporary
+// password is generated and returned once. The worker's sessions and any
+// login lockout are cleared.
+func (h *Handler) ResetWorkerPassword(c *gin.Context) {
+	workerId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workers"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worker ID"})
+		return
+	}
+	var req struct {
+		NewPassword string `json:"new_password"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+	orgId := c.GetInt("organisation_id")
+	worker, err := h.store.Workers.GetWorker(workerId, orgId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worker not found or not authorized"})
 		return
 	}
-	defer rows.Close()
-	var workers []models.Worker
-	for rows.Next() {
-		var w models.Worker
-		err := rows.Scan(&w.ID, &w.Username, &w.PasswordHash, &w.AdminID, &w.Name, &w.Phone, &w.CreatedAt)
-		if err == nil {
-			workers = append(workers, w)
+	password := req.NewPassword
+	if password == "" {
+		if password, err = h.newTemporaryPassword(worker.Username); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate password"})
+			return
 		}
+	} else if err := h.checkPassword(password, worker.Username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hash, _ := HashPassword(password)
+	if err := h.store.Workers.UpdateWorkerPassword(workerId, orgId, hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		return
+	}
+	if _, err := h.store.Tokens.RevokeUserSessions("worker", workerId); err != nil {
+		log.Printf("Failed to revoke sessions of worker %d: %v", workerId, err)
+	}
+	if err := h.guard.Unlock(lockout.UserKey("worker", strings.ToLower(worker.Username))); err != nil {
+		log.Printf("Failed to unlock worker %d: %v", workerId, err)
+	}
+	if req.NewPassword == "" {
+		c.JSON(http.StatusOK, gin.H{"message": "Password reset", "temporaryPassword": password})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset"})
+}
+
+// Worker changes their password
+func (h *Handler) ChangeWorkerPassword(c *gin.Context) {
+	workerId := c.GetInt("worker_id")
+	orgId := c.GetInt("organisation_id")
+	var req struct {
+		OldPassword string `json:"old_password"`
+		NewPassword string `json:"new_password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	worker, err := h.store.Workers.GetWorker(workerId, orgId)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worker not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch worker"})
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(worker.PasswordHash), []byte(req.OldPassword)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Old password incorrect"})
+		return
+	}
+	if err := h.checkPassword(req.NewPassword, worker.Username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hash, _ := HashPassword(req.NewPassword)
+	if err := h.store.Workers.UpdateWorkerPassword(workerId, orgId, hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		return
+	}
+	// Sign out the worker's other devices, which may be why the password changed
+	if _, err := h.store.Tokens.RevokeOtherSessions("worker", workerId, c.GetString("session_id")); err != nil {
+		log.Printf("Failed to revoke other sessions of worker %d: %v", workerId, err)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated"})
+}
+
+// Admin restores a deleted worker
+func (h *Handler) RestoreWorker(c *gin.Context) {
+	workerId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worker ID"})
+		return
+	}
+	orgId := c.GetInt("organisation_id")
+	err = h.store.Workers.RestoreWorker(workerId, orgId)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Deleted worker not found or not authorized"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore worker"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Worker restored"})
+}
+
+// Admin lists all workers; ?deleted=true lists the deleted ones instead
+func (h *Handler) ListWorkers(c *gin.Context) {
+	orgId := c.GetInt("organisation_id")
+	filter := store.WorkerFilter{Deleted: c.Query("deleted") == "true"}
+	workers, err := h.store.Workers.ListWorkers(orgId, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workers"})
+		return
 	}
 	c.JSON(http.StatusOK, workers)
 }
@@ -0,0 +1,109 @@
+// Package lifecycle defines the statuses a project moves through, from the
+// first measurement to the closed job, and who may move it between them.
+package lifecycle
+
+import (
+	"errors"
+	"slices"
+)
+
+// Project statuses, in the order a job normally moves through them
+const (
+	Measured    = "measured"
+	Quoted      = "quoted"
+	Approved    = "approved"
+	AdvancePaid = "advance_paid"
+	InStitching = "in_stitching"
+	Installed   = "installed"
+	Closed      = "closed"
+)
+
+// Statuses lists every status in order; a new project starts at the first
+var Statuses = []string{Measured, Quoted, Approved, AdvancePaid, InStitching, Installed, Closed}
+
+// Roles that can change a project's status
+const (
+	RoleAdmin  = "admin"
+	RoleWorker = "worker"
+)
+
+var (
+	// ErrUnknownStatus is returned for a status that is not in Statuses
+	ErrUnknownStatus = errors.New("unknown project status")
+	// ErrNotAllowed is returned when there is no transition between two statuses
+	ErrNotAllowed = errors.New("transition not allowed")
+	// ErrForbidden is returned when the transition exists but not for the role
+	ErrForbidden = errors.New("transition not permitted for this role")
+)
+
+// Transition is a permitted status change and the roles that may make it
+type Transition struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Roles []string `json:"roles"`
+}
+
+// Transitions is the complete transition table. Workers record what happens
+// on site (re-measuring, installation); admins handle pricing, payment and
+// closing the job.
+var Transitions = []Transition{
+	{From: Measured, To: Quoted, Roles: []string{RoleAdmin}},
+	{From: Quoted, To: Measured, Roles: []string{RoleAdmin, RoleWorker}},
+	{From: Quoted, To: Approved, Roles: []string{RoleAdmin}},
+	{From: Approved, To: AdvancePaid, Roles: []string{RoleAdmin}},
+	{From: AdvancePaid, To: InStitching, Roles: []string{RoleAdmin}},
+	{From: InStitching, To: Installed, Roles: []string{RoleAdmin, RoleWorker}},
+	{From: Installed, To: Closed, Roles: []string{RoleAdmin}},
+}
+
+// Valid reports whether status is a known status
+func Valid(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Check reports whether role may move a project from one status to another
+func Check(from, to, role string) error {
+	if !Valid(to) {
+		return ErrUnknownStatus
+	}
+	for _, t := range Transitions {
+		if t.From != from || t.To != to {
+			continue
+		}
+		for _, r := range t.Roles {
+			if r == role {
+				return nil
+			}
+		}
+		return ErrForbidden
+	}
+	return ErrNotAllowed
+}
+
+// Next lists the statuses role may move a project to from its current status
+func Next(from, role string) []string {
+	next := []string{}
+	for _, t := range Transitions {
+		if t.From == from && Check(from, t.To, role) == nil {
+			next = append(next, t.To)
+		}
+	}
+	return next
+}
+
+// Completed reports whether a status counts as completed for the legacy
+// isCompleted flag
+func Completed(status string) bool {
+	return status == Installed || status == Closed
+}
+
+// Ordered reports whether the customer has accepted the quotation: the
+// project is approved or past it
+func Ordered(status string) bool {
+	return slices.Index(Statuses, status) >= slices.Index(Statuses, Approved)
+}
@@ -0,0 +1,104 @@
+package lifecycle
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		from, to, role string
+		want           error
+	}{
+		{Measured, Quoted, RoleAdmin, nil},
+		{Measured, Quoted, RoleWorker, ErrForbidden},
+		{Quoted, Measured, RoleAdmin, nil},
+		{Quoted, Measured, RoleWorker, nil},
+		{Quoted, Approved, RoleAdmin, nil},
+		{Approved, AdvancePaid, RoleAdmin, nil},
+		{AdvancePaid, InStitching, RoleAdmin, nil},
+		{AdvancePaid, InStitching, RoleWorker, ErrForbidden},
+		{InStitching, Installed, RoleAdmin, nil},
+		{InStitching, Installed, RoleWorker, nil},
+		{Installed, Closed, RoleAdmin, nil},
+		{Installed, Closed, RoleWorker, ErrForbidden},
+		{Measured, Installed, RoleAdmin, ErrNotAllowed},
+		{Closed, Measured, RoleAdmin, ErrNotAllowed},
+		{Installed, InStitching, RoleWorker, ErrNotAllowed},
+		{Measured, Measured, RoleAdmin, ErrNotAllowed},
+		{Measured, "completed", RoleAdmin, ErrUnknownStatus},
+	}
+	for _, tt := range tests {
+		if got := Check(tt.from, tt.to, tt.role); got != tt.want {
+			t.Errorf("Check(%s, %s, %s) = %v, want %v", tt.from, tt.to, tt.role, got, tt.want)
+		}
+	}
+}
+
+// TestTransitions checks that the table only names known statuses and roles,
+// and that every status can be reached from the first
+func TestTransitions(t *testing.T) {
+	reached := map[string]bool{Statuses[0]: true}
+	for _, tr := range Transitions {
+		if !Valid(tr.From) || !Valid(tr.To) || tr.From == tr.To {
+			t.Errorf("transition %s -> %s", tr.From, tr.To)
+		}
+		if len(tr.Roles) == 0 {
+			t.Errorf("transition %s -> %s has no roles", tr.From, tr.To)
+		}
+		for _, r := range tr.Roles {
+			if r != RoleAdmin && r != RoleWorker {
+				t.Errorf("transition %s -> %s names role %q", tr.From, tr.To, r)
+			}
+		}
+		reached[tr.To] = true
+	}
+	for _, s := range Statuses {
+		if !reached[s] {
+			t.Errorf("no transition reaches %s", s)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		from, role string
+		want       []string
+	}{
+		{Measured, RoleAdmin, []string{Quoted}},
+		{Measured, RoleWorker, []string{}},
+		{Quoted, RoleAdmin, []string{Measured, Approved}},
+		{Quoted, RoleWorker, []string{Measured}},
+		{InStitching, RoleWorker, []string{Installed}},
+		{Installed, RoleAdmin, []string{Closed}},
+		{Closed, RoleAdmin, []string{}},
+	}
+	for _, tt := range tests {
+		if got := Next(tt.from, tt.role); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Next(%s, %s) = %v, want %v", tt.from, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestCompletedAndOrdered(t *testing.T) {
+	tests := []struct {
+		status             string
+		completed, ordered bool
+	}{
+		{Measured, false, false},
+		{Quoted, false, false},
+		{Approved, false, true},
+		{AdvancePaid, false, true},
+		{InStitching, false, true},
+		{Installed, true, true},
+		{Closed, true, true},
+	}
+	for _, tt := range tests {
+		if got := Completed(tt.status); got != tt.completed {
+			t.Errorf("Completed(%s) = %v", tt.status, got)
+		}
+		if got := Ordered(tt.status); got != tt.ordered {
+			t.Errorf("Ordered(%s) = %v", tt.status, got)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package lockout slows down password guessing. Every failed login counts
+// against the username and the client IP; each further attempt has to wait
+// twice as long as the one before, and after too many failures the key is
+// locked out for a while.
+package lockout
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the failure count of one key since its last success or lockout
+type State struct {
+	Failures    int
+	LastFailure time.Time
+}
+
+// Store keeps the failure state of each key. MemoryStore keeps it in process;
+// a shared implementation lets several servers count failures together.
+type Store interface {
+	// Get returns the state of key, or a zero State if it has none
+	Get(key string) (State, error)
+	// RecordFailure adds a failure at the given time and returns the new
+	// state. Failures older than window are forgotten first.
+	RecordFailure(key string, at time.Time, window time.Duration) (State, error)
+	Reset(key string) error
+}
+
+// Policy sets how quickly keys are slowed down and locked. MaxFailures
+// applies to usernames and IPMaxFailures to client IPs, which several users
+// may share.
+type Policy struct {
+	MaxFailures   int
+	IPMaxFailures int
+	Backoff       time.Duration
+	Lockout       time.Duration
+}
+
+// Guard applies a Policy to login attempts
+type Guard struct {
+	store  Store
+	policy Policy
+	now    func() time.Time
+}
+
+// New creates a Guard that keeps its state in s
+func New(s Store, p Policy) *Guard {
+	return &Guard{store: s, policy: p, now: time.Now}
+}
+
+// UserKey and IPKey name the counters of an account and of a client
+func UserKey(role, username string) string { return role + ":" + username }
+func IPKey(ip string) string               { return "ip:" + ip }
+
+// Wait returns how long the caller must wait before the next attempt for
+// the account or from the IP is allowed, and whether that is because of a
+// lockout rather than backoff. A zero wait means the attempt may go ahead.
+func (g *Guard) Wait(userKey, ipKey string) (wait time.Duration, locked bool, err error) {
+	for _, k := range []struct {
+		key string
+		max int
+	}{{userKey, g.policy.MaxFailures}, {ipKey, g.policy.IPMaxFailures}} {
+		st, err := g.store.Get(k.key)
+		if err != nil {
+			return 0, false, err
+		}
+		w, l := g.wait(st, k.max)
+		if w > wait {
+			wait = w
+		}
+		locked = locked || l
+	}
+	return wait, locked, nil
+}
+
+func (g *Guard) wait(st State, max int) (time.Duration, bool) {
+	if st.Failures == 0 {
+		return 0, false
+	}
+	var until time.Time
+	locked := st.Failures >= max
+	if locked {
+		until = st.LastFailure.Add(g.policy.Lockout)
+	} else {
+		delay := g.policy.Backoff << (st.Failures - 1)
+		if delay <= 0 || delay > g.policy.Lockout {
+			delay = g.policy.Lockout
+		}
+		until = st.LastFailure.Add(delay)
+	}
+	wait := until.Sub(g.now())
+	if wait <= 0 {
+		return 0, false
+	}
+	return wait, locked
+}
+
+// Fail records a failed attempt for the account and the IP
+func (g *Guard) Fail(userKey, ipKey string) error {
+	now := g.now()
+	for _, key := range []string{userKey, ipKey} {
+		if _, err := g.store.RecordFailure(key, now, g.policy.Lockout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Succeed clears the account's failures. The IP's are kept, so that one
+// valid account cannot be used to keep guessing others from the same IP.
+func (g *Guard) Succeed(userKey string) error {
+	return g.store.Reset(userKey)
+}
+
+// Unlock clears an account's failures, lifting any lockout
+func (g *Guard) Unlock(userKey string) error {
+	return g.store.Reset(userKey)
+}
+
+// MemoryStore is a Store for a single server process
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+	pruned time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]State)}
+}
+
+func (m *MemoryStore) Get(key string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states[key], nil
+}
+
+func (m *MemoryStore) RecordFailure(key string, at time.Time, window time.Duration) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Drop forgotten keys now and then so the map does not keep every IP seen
+	if at.Sub(m.pruned) > window {
+		for k, st := range m.states {
+			if at.Sub(st.LastFailure) > window {
+				delete(m.states, k)
+			}
+		}
+		m.pruned = at
+	}
+	st := m.states[key]
+	if at.Sub(st.LastFailure) > window {
+		st = State{}
+	}
+	st.Failures++
+	st.LastFailure = at
+	m.states[key] = st
+	return st, nil
+}
+
+func (m *MemoryStore) Reset(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states, key)
+	return nil
+}
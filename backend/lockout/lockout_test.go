@@ -0,0 +1,96 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{MaxFailures: 3, IPMaxFailures: 5, Backoff: time.Second, Lockout: 10 * time.Minute}
+
+// newTestGuard returns a Guard whose clock only moves when the returned
+// function advances it
+func newTestGuard(p Policy) (*Guard, func(time.Duration)) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	g := New(NewMemoryStore(), p)
+	g.now = func() time.Time { return now }
+	return g, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		policy   Policy
+		failures int
+		wait     time.Duration
+		locked   bool
+	}{
+		{testPolicy, 0, 0, false},
+		{testPolicy, 1, time.Second, false},
+		{testPolicy, 2, 2 * time.Second, false},
+		{testPolicy, 3, 10 * time.Minute, true},
+		{testPolicy, 4, 10 * time.Minute, true},
+		// The doubling delay never exceeds the lockout
+		{Policy{MaxFailures: 100, IPMaxFailures: 100, Backoff: time.Second, Lockout: 10 * time.Minute}, 10, 512 * time.Second, false},
+		{Policy{MaxFailures: 100, IPMaxFailures: 100, Backoff: time.Second, Lockout: 10 * time.Minute}, 11, 10 * time.Minute, false},
+		{Policy{MaxFailures: 100, IPMaxFailures: 100, Backoff: time.Second, Lockout: 10 * time.Minute}, 70, 10 * time.Minute, false},
+	}
+	for _, tt := range tests {
+		g, _ := newTestGuard(tt.policy)
+		for i := 0; i < tt.failures; i++ {
+			if err := g.Fail(UserKey("admin", "anitha"), IPKey("10.0.0.1")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		wait, locked, err := g.Wait(UserKey("admin", "anitha"), IPKey("10.0.0.1"))
+		if err != nil || wait != tt.wait || locked != tt.locked {
+			t.Errorf("after %d failures: Wait = %v, %v, %v, want %v, %v", tt.failures, wait, locked, err, tt.wait, tt.locked)
+		}
+	}
+}
+
+func TestLockout(t *testing.T) {
+	g, advance := newTestGuard(testPolicy)
+	anitha, bala, ravi := UserKey("admin", "anitha"), UserKey("admin", "bala"), UserKey("worker", "ravi")
+	office, home := IPKey("10.0.0.1"), IPKey("10.0.0.2")
+	fail := func(user, ip string) {
+		t.Helper()
+		if err := g.Fail(user, ip); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	steps := []struct {
+		name   string
+		do     func()
+		user   string
+		ip     string
+		wait   time.Duration
+		locked bool
+	}{
+		{"three failures lock the account", func() { fail(anitha, office); fail(anitha, office); fail(anitha, office) }, anitha, office, 10 * time.Minute, true},
+		{"the lockout applies from another IP", nil, anitha, home, 10 * time.Minute, true},
+		{"another account only waits out the IP's backoff", nil, bala, office, 4 * time.Second, false},
+		{"the lockout runs down", func() { advance(4 * time.Minute) }, anitha, home, 6 * time.Minute, true},
+		{"and ends", func() { advance(6 * time.Minute) }, anitha, home, 0, false},
+		{"failures after it count from one", func() { advance(time.Second); fail(anitha, home) }, anitha, home, time.Second, false},
+		{"unlock clears the account", func() { fail(anitha, home); fail(anitha, home); g.Unlock(anitha) }, anitha, IPKey("10.0.0.3"), 0, false},
+		{"five failures from one IP lock it for every account", func() {
+			advance(time.Hour)
+			fail(bala, office)
+			fail(ravi, office)
+			fail(bala, office)
+			fail(ravi, office)
+			fail(bala, office)
+		}, UserKey("admin", "new"), office, 10 * time.Minute, true},
+		{"success clears the account", func() { g.Succeed(ravi) }, ravi, home, 0, false},
+		{"but not the IP", nil, ravi, office, 10 * time.Minute, true},
+	}
+	for _, s := range steps {
+		if s.do != nil {
+			s.do()
+		}
+		wait, locked, err := g.Wait(s.user, s.ip)
+		if err != nil || wait != s.wait || locked != s.locked {
+			t.Errorf("%s: Wait(%s, %s) = %v, %v, %v, want %v, %v", s.name, s.user, s.ip, wait, locked, err, s.wait, s.locked)
+		}
+	}
+}
@@ -5,29 +5,70 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/Vanaraj10/interior-backend/access"
+	"github.com/Vanaraj10/interior-backend/auth"
 	"github.com/Vanaraj10/interior-backend/config"
 	"github.com/Vanaraj10/interior-backend/handlers"
+	"github.com/Vanaraj10/interior-backend/lockout"
 	"github.com/Vanaraj10/interior-backend/middleware"
+	"github.com/Vanaraj10/interior-backend/migrations"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/Vanaraj10/interior-backend/store/memory"
+	"github.com/Vanaraj10/interior-backend/store/sqlstore"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 )
 
 func main() {
-	godotenv.Load()
-	config.ConnectAzureSQL() // Connect to Azure SQL at startup
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		db, err := config.OpenDB(cfg.Store.Driver, cfg.Database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		runMigrateCommand(db, cfg.Store.Driver, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		runBackfillCommand(openStore(cfg), os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		runAdminCommand(openStore(cfg), cfg, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "purge" {
+		runPurgeCommand(openStore(cfg), cfg.Purge)
+		return
+	}
 
-	// Create database tables if they don't exist
-	createDatabaseTables()
+	s := openStore(cfg)
+	startPurgeJob(s, cfg.Purge)
+	keys, err := auth.New(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	guard := lockout.New(lockout.NewMemoryStore(), lockout.Policy{
+		MaxFailures:   cfg.Login.MaxFailures,
+		IPMaxFailures: cfg.Login.IPMaxFailures,
+		Backoff:       cfg.Login.Backoff,
+		Lockout:       cfg.Login.Lockout,
+	})
+	h := handlers.New(cfg, s, keys, guard)
 
 	r := gin.Default()
 
-	// Add CORS middleware (allow all origins, methods, and headers)
+	// Add CORS middleware for the configured origins
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"}, // Allow all origins
+		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "X-Total-Count", "X-Completed-Count"},
 		AllowCredentials: true,
 	}))
 
@@ -35,117 +76,138 @@ func main() {
 	r.GET("/api/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
-	r.POST("/api/admin/login", handlers.AdminLogin)
-	r.POST("/api/worker/login", handlers.WorkerLogin)
-	adminGroup := r.Group("/api/admin").Use(middleware.AdminAuthMiddleware())
+	r.GET("/api/schemas", h.ListSchemas)
+	r.GET("/api/project-statuses", h.ListProjectStatuses)
+	r.GET("/api/setup", h.GetSetupStatus)
+	r.POST("/api/setup", h.Setup)
+	r.POST("/api/signup", h.Signup)
+	r.POST("/api/admin/login", h.AdminLogin)
+	r.POST("/api/worker/login", h.WorkerLogin)
+	r.POST("/api/auth/refresh", h.RefreshTokens)
+	r.GET("/api/.well-known/jwks.json", h.JWKS)
+	audit := middleware.Audit(s.Audit, h.AuditLoaders())
+	adminGroup := r.Group("/api/admin").Use(middleware.AdminAuthMiddleware(keys, s.Tokens, s.Admins), audit)
 	{
-		adminGroup.POST("/workers", handlers.CreateWorker)
-		adminGroup.DELETE("/workers/:id", handlers.DeleteWorker)
-		adminGroup.GET("/workers", handlers.ListWorkers)
-		adminGroup.GET("/projects", handlers.ListProjects)
-		adminGroup.GET("/projects/:id", handlers.GetProject)
-		adminGroup.GET("/projects/:id/stitching-quotation", handlers.GenerateStitchingQuotation)
-		adminGroup.PUT("/projects/:id/completed", handlers.ToggleProjectCompleted)
-		adminGroup.DELETE("/projects/:id", handlers.DeleteProject)
-		adminGroup.PUT("/password", handlers.ChangeAdminPassword)
+		adminGroup.GET("/audit", middleware.Require(access.ViewAudit), h.ListAudit)
+		adminGroup.POST("/logout", h.Logout)
+		adminGroup.POST("/workers", middleware.Require(access.ManageWorkers), h.CreateWorker)
+		adminGroup.PUT("/workers/:id", middleware.Require(access.ManageWorkers), h.UpdateWorker)
+		adminGroup.DELETE("/workers/:id", middleware.Require(access.ManageWorkers), h.DeleteWorker)
+		adminGroup.POST("/workers/:id/reset-password", middleware.Require(access.ManageWorkers), h.ResetWorkerPassword)
+		adminGroup.POST("/workers/:id/restore", middleware.Require(access.ManageWorkers), h.RestoreWorker)
+		adminGroup.POST("/workers/:id/revoke-sessions", middleware.Require(access.ManageWorkers), h.RevokeWorkerSessions)
+		adminGroup.POST("/workers/:id/unlock", middleware.Require(access.ManageWorkers), h.UnlockWorker)
+		adminGroup.GET("/failed-logins", middleware.Require(access.ViewAudit), h.ListFailedLogins)
+		adminGroup.GET("/workers", middleware.Require(access.ViewData), h.ListWorkers)
+		adminGroup.GET("/projects", middleware.Require(access.ViewData), h.ListProjects)
+		adminGroup.GET("/search", middleware.Require(access.ViewData), h.Search)
+		adminGroup.GET("/customers/:id", middleware.Require(access.ViewData), h.GetCustomer)
+		adminGroup.POST("/customers/:id/merge", middleware.Require(access.ManageProjects), h.MergeCustomer)
+		adminGroup.GET("/projects/:id", middleware.Require(access.ViewData), h.GetProject)
+		adminGroup.GET("/projects/:id/stitching-quotation", middleware.Require(access.ViewData), h.GenerateStitchingQuotation)
+		adminGroup.GET("/projects/:id/pricing", middleware.Require(access.ViewData), h.GetProjectPricing)
+		adminGroup.GET("/projects/:id/measurements", middleware.Require(access.ViewData), h.GetProjectMeasurements)
+		adminGroup.GET("/projects/:id/quotation.pdf", middleware.Require(access.ViewData), h.GetQuotationPDF)
+		adminGroup.PUT("/projects/:id/completed", middleware.Require(access.ManageProjects), h.ToggleProjectCompleted)
+		adminGroup.PUT("/projects/:id/status", middleware.Require(access.ChangeProjectStatus), h.UpdateProjectStatus)
+		adminGroup.GET("/projects/:id/status-history", middleware.Require(access.ViewData), h.GetProjectStatusHistory)
+		adminGroup.GET("/projects/:id/revisions", middleware.Require(access.ViewData), h.ListProjectRevisions)
+		adminGroup.GET("/projects/:id/revisions/diff", middleware.Require(access.ViewData), h.DiffProjectRevisions)
+		adminGroup.GET("/projects/:id/revisions/:revision", middleware.Require(access.ViewData), h.GetProjectRevision)
+		adminGroup.DELETE("/projects/:id", middleware.Require(access.ManageProjects), h.DeleteProject)
+		adminGroup.POST("/projects/:id/restore", middleware.Require(access.ManageProjects), h.RestoreProject)
+		adminGroup.PUT("/password", h.ChangeAdminPassword)
+
+		// Organisation and admin account routes
+		adminGroup.GET("/me", h.GetCurrentAdmin)
+		adminGroup.GET("/organisation", middleware.Require(access.ViewData), h.GetOrganisation)
+		adminGroup.PUT("/organisation", middleware.Require(access.ManageOrganisation), h.UpdateOrganisation)
+		adminGroup.POST("/admins", middleware.Require(access.ManageOrganisation), h.CreateAdmin)
+		adminGroup.PUT("/admins/:id", middleware.Require(access.ManageOrganisation), h.UpdateAdminRole)
+		adminGroup.DELETE("/admins/:id", middleware.Require(access.ManageOrganisation), h.DeleteAdmin)
+
+		// Stitching sheet template routes
+		adminGroup.GET("/templates/stitching", middleware.Require(access.ViewData), h.GetStitchingTemplate)
+		adminGroup.PUT("/templates/stitching", middleware.Require(access.ManageTemplates), h.UpdateStitchingTemplate)
+		adminGroup.DELETE("/templates/stitching", middleware.Require(access.ManageTemplates), h.ResetStitchingTemplate)
+		adminGroup.POST("/templates/stitching/preview", middleware.Require(access.ManageTemplates), h.PreviewStitchingTemplate)
 
 		// Brand routes
-		adminGroup.POST("/brands", handlers.CreateBrand)
-		adminGroup.GET("/brands", handlers.ListBrands)
-		adminGroup.GET("/brands/:id", handlers.GetBrand)
-		adminGroup.PUT("/brands/:id", handlers.UpdateBrand)
-		adminGroup.DELETE("/brands/:id", handlers.DeleteBrand)
+		adminGroup.POST("/brands", middleware.Require(access.ManageCatalog), h.CreateBrand)
+		adminGroup.GET("/brands", middleware.Require(access.ViewData), h.ListBrands)
+		adminGroup.GET("/brands/:id", middleware.Require(access.ViewData), h.GetBrand)
+		adminGroup.PUT("/brands/:id", middleware.Require(access.ManageCatalog), h.UpdateBrand)
+		adminGroup.DELETE("/brands/:id", middleware.Require(access.ManageCatalog), h.DeleteBrand)
 
 		// Folder routes
-		adminGroup.POST("/folders", handlers.CreateFolder)
-		adminGroup.GET("/folders", handlers.ListFolders)
-		adminGroup.GET("/folders/:id", handlers.GetFolder)
-		adminGroup.PUT("/folders/:id", handlers.UpdateFolder)
-		adminGroup.DELETE("/folders/:id", handlers.DeleteFolder)
+		adminGroup.POST("/folders", middleware.Require(access.ManageCatalog), h.CreateFolder)
+		adminGroup.GET("/folders", middleware.Require(access.ViewData), h.ListFolders)
+		adminGroup.GET("/folders/:id", middleware.Require(access.ViewData), h.GetFolder)
+		adminGroup.PUT("/folders/:id", middleware.Require(access.ManageCatalog), h.UpdateFolder)
+		adminGroup.DELETE("/folders/:id", middleware.Require(access.ManageCatalog), h.DeleteFolder)
 
 		// Cloth routes
-		adminGroup.POST("/cloths", handlers.CreateCloth)
-		adminGroup.GET("/cloths", handlers.ListCloths)
-		adminGroup.GET("/cloths/:id", handlers.GetCloth)
-		adminGroup.PUT("/cloths/:id", handlers.UpdateCloth)
-		adminGroup.DELETE("/cloths/:id", handlers.DeleteCloth)
+		adminGroup.POST("/cloths", middleware.Require(access.ManageCatalog), h.CreateCloth)
+		adminGroup.GET("/cloths", middleware.Require(access.ViewData), h.ListCloths)
+		adminGroup.GET("/cloths/:id", middleware.Require(access.ViewData), h.GetCloth)
+		adminGroup.PUT("/cloths/:id", middleware.Require(access.ManageCatalog), h.UpdateCloth)
+		adminGroup.DELETE("/cloths/:id", middleware.Require(access.ManageCatalog), h.DeleteCloth)
 	}
 
-	workerGroup := r.Group("/api/worker").Use(middleware.WorkerAuthMiddleware())
+	workerGroup := r.Group("/api/worker").Use(middleware.WorkerAuthMiddleware(keys, s.Tokens, s.Workers), audit)
 	{
-		workerGroup.POST("/projects", handlers.CreateProject)
-		workerGroup.PUT("/projects/:id/completed", handlers.WorkerToggleProjectCompleted)
+		workerGroup.POST("/logout", h.Logout)
+		workerGroup.PUT("/password", h.ChangeWorkerPassword)
+		workerGroup.POST("/projects", h.CreateProject)
+		workerGroup.GET("/projects", h.ListWorkerProjects)
+		workerGroup.GET("/projects/:id", h.GetWorkerProject)
+		workerGroup.PUT("/projects/:id/completed", h.WorkerToggleProjectCompleted)
+		workerGroup.PUT("/projects/:id/status", h.WorkerUpdateProjectStatus)
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	r.Run(":" + port) // listen and serve on 0.0.0.0:PORT
+	r.Run(":" + cfg.Port) // listen and serve on 0.0.0.0:PORT
 }
 
-// createDatabaseTables creates the necessary database tables if they don't exist
-func createDatabaseTables() {
-	// Create brands table
-	brandsTable := `
-	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='brands' and xtype='U')
-	CREATE TABLE brands (
-		id INT IDENTITY(1,1) PRIMARY KEY,
-		name NVARCHAR(100) NOT NULL,
-		description NVARCHAR(MAX),
-		logo_url NVARCHAR(255),
-		admin_id INT NOT NULL,
-		is_active BIT NOT NULL DEFAULT 1,
-		created_at DATETIME NOT NULL,
-		updated_at DATETIME NOT NULL
-	)
-	`
-	_, err := config.GetDB().Exec(brandsTable)
-	if err != nil {
-		log.Printf("Error creating brands table: %v", err)
+// openStore selects the storage backend: "mssql" (default), "postgres" or
+// "memory"
+func openStore(cfg *config.Config) *store.Store {
+	if cfg.Store.Driver == "memory" {
+		s := memory.New()
+		seedAdmin(s, cfg.SeedAdmin)
+		log.Println("Using in-memory store; data will be lost on restart")
+		return s
 	}
 
-	// Create folders table
-	foldersTable := `
-	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='folders' and xtype='U')
-	CREATE TABLE folders (
-		id INT IDENTITY(1,1) PRIMARY KEY,
-		name NVARCHAR(100) NOT NULL,
-		description NVARCHAR(MAX),
-		brand_id INT NOT NULL,
-		admin_id INT NOT NULL,
-		is_active BIT NOT NULL DEFAULT 1,
-		created_at DATETIME NOT NULL,
-		updated_at DATETIME NOT NULL,
-		FOREIGN KEY (brand_id) REFERENCES brands(id)
-	)
-	`
-	_, err = config.GetDB().Exec(foldersTable)
+	db, err := config.OpenDB(cfg.Store.Driver, cfg.Database)
 	if err != nil {
-		log.Printf("Error creating folders table: %v", err)
+		log.Fatal(err)
+	}
+
+	// Apply any pending schema migrations
+	if _, err := migrations.Up(db, cfg.Store.Driver); err != nil {
+		log.Fatalf("Failed to apply migrations: %v", err)
+	}
+	if cfg.Store.Driver == "postgres" {
+		log.Println("Connected to PostgreSQL!")
+		return sqlstore.New(db, sqlstore.Postgres)
 	}
+	log.Println("Connected to Azure SQL Database!")
+	return sqlstore.New(db, sqlstore.MSSQL)
+}
 
-	// Create cloths table
-	clothsTable := `
-	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='cloths' and xtype='U')
-	CREATE TABLE cloths (
-		id INT IDENTITY(1,1) PRIMARY KEY,
-		name NVARCHAR(100) NOT NULL,
-		rate DECIMAL(10, 2) NOT NULL,
-		description NVARCHAR(MAX),
-		image_url NVARCHAR(255),
-		folder_id INT NOT NULL,
-		brand_id INT NOT NULL,
-		admin_id INT NOT NULL,
-		is_active BIT NOT NULL DEFAULT 1,
-		created_at DATETIME NOT NULL,
-		updated_at DATETIME NOT NULL,
-		FOREIGN KEY (folder_id) REFERENCES folders(id),
-		FOREIGN KEY (brand_id) REFERENCES brands(id)
-	)
-	`
-	_, err = config.GetDB().Exec(clothsTable)
+// seedAdmin creates the configured seed admin, if set, as the owner of a new
+// organisation so an empty in-memory store can be logged into
+func seedAdmin(s *store.Store, seed config.SeedAdminConfig) {
+	if seed.Username == "" || seed.Password == "" {
+		return
+	}
+	hash, err := handlers.HashPassword(seed.Password)
 	if err != nil {
-		log.Printf("Error creating cloths table: %v", err)
+		log.Fatalf("Failed to hash seed admin password: %v", err)
+	}
+	org := models.Organisation{Name: seed.Username}
+	admin := models.Admin{Username: seed.Username, PasswordHash: hash, Role: access.Owner}
+	if err := s.Admins.CreateOrganisation(&org, &admin); err != nil {
+		log.Fatalf("Failed to seed admin: %v", err)
 	}
 }
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/gin-gonic/gin"
+)
+
+// auditEntityTypes names the entity behind each route's first path segment
+var auditEntityTypes = map[string]string{
+	"projects":     "project",
+	"customers":    "customer",
+	"workers":      "worker",
+	"brands":       "brand",
+	"folders":      "folder",
+	"cloths":       "cloth",
+	"templates":    "template",
+	"organisation": "organisation",
+	"admins":       "admin",
+}
+
+// Audit records every mutating request of an authenticated group in the audit
+// log. It must run after the auth middleware. The entity is identified by the
+// route's :id parameter, or by the audit_entity_id a handler sets when it
+// creates one. loaders fetch an entity of the given type for the before and
+// after snapshots; entity types without a loader are recorded without them.
+func Audit(s store.AuditStore, loaders map[string]func(id, orgID int) (interface{}, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		orgID := c.GetInt("organisation_id")
+		entityType := auditEntityType(c.FullPath())
+		load := loaders[entityType]
+		snapshot := func(id *int) json.RawMessage {
+			if id == nil || load == nil {
+				return nil
+			}
+			v, err := load(*id, orgID)
+			if err != nil {
+				return nil
+			}
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil
+			}
+			return data
+		}
+
+		var entityID *int
+		if id, err := strconv.Atoi(c.Param("id")); err == nil {
+			entityID = &id
+		}
+		before := snapshot(entityID)
+
+		c.Next()
+
+		if id, ok := c.Get("audit_entity_id"); ok && entityID == nil {
+			if id, ok := id.(int); ok {
+				entityID = &id
+			}
+		}
+		e := models.AuditEntry{
+			OrganisationID: orgID,
+			ActorRole:      "admin",
+			ActorID:        c.GetInt("admin_id"),
+			Method:         c.Request.Method,
+			Route:          c.FullPath(),
+			Path:           c.Request.URL.Path,
+			EntityType:     entityType,
+			EntityID:       entityID,
+			Status:         c.Writer.Status(),
+			Before:         before,
+			ClientIP:       c.ClientIP(),
+		}
+		if workerID, ok := c.Get("worker_id"); ok {
+			e.ActorRole = "worker"
+			e.ActorID = workerID.(int)
+		}
+		if e.Status < http.StatusBadRequest && e.Method != http.MethodDelete {
+			e.After = snapshot(entityID)
+		}
+		if err := s.RecordAudit(&e); err != nil {
+			log.Printf("Failed to record audit entry for %s %s: %v", e.Method, e.Path, err)
+		}
+	}
+}
+
+// auditEntityType derives the entity type from a route such as /api/admin/projects/:id
+func auditEntityType(route string) string {
+	parts := strings.Split(strings.TrimPrefix(route, "/api/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	if t, ok := auditEntityTypes[parts[1]]; ok {
+		return t
+	}
+	return parts[1]
+}
@@ -2,57 +2,96 @@ package middleware
 
 import (
 	"net/http"
-	"os"
 	"strings"
 
+	"github.com/Vanaraj10/interior-backend/access"
+	"github.com/Vanaraj10/interior-backend/auth"
+	"github.com/Vanaraj10/interior-backend/store"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var JWTSecret = []byte(getJWTSecret())
-
-func getJWTSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "supersecretkey" // fallback for local dev
-	}
-	return secret
-}
-
-// AdminAuthMiddleware checks for a valid admin JWT and sets admin_id in context
-func AdminAuthMiddleware() gin.HandlerFunc {
+// AdminAuthMiddleware checks for a valid admin JWT whose session has not been
+// revoked and whose admin still exists, and sets admin_id, organisation_id,
+// admin_role and session_id in context. The role is read from the store on
+// every request, so role changes apply at once.
+func AdminAuthMiddleware(keys *auth.Keys, tokens store.TokenStore, admins store.AdminStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		header := c.GetHeader("Authorization")
-		if header == "" || !strings.HasPrefix(header, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid Authorization header"})
-			c.Abort()
+		claims, ok := sessionClaims(c, keys, tokens, "admin")
+		if !ok {
 			return
 		}
-		tokenStr := strings.TrimPrefix(header, "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return JWTSecret, nil
-		})
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		adminID, ok := claims["user_id"].(float64) // JWT stores numbers as float64
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token payload"})
 			c.Abort()
 			return
 		}
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok || claims["role"] != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		admin, err := admins.GetAdmin(int(adminID))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin account no longer exists"})
 			c.Abort()
 			return
 		}
-		adminID, ok := claims["user_id"].(float64) // JWT stores numbers as float64
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token payload"})
+		c.Set("admin_id", admin.ID)
+		c.Set("organisation_id", admin.OrganisationID)
+		c.Set("admin_role", admin.Role)
+		c.Next()
+	}
+}
+
+// Require lets the request through only if the admin's role grants p. It
+// must run after AdminAuthMiddleware.
+func Require(p access.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !access.Allowed(c.GetString("admin_role"), p) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your role does not allow this", "permission": p})
 			c.Abort()
 			return
 		}
-		id := int(adminID)
-		// Set both snake_case and camelCase for backward compatibility across handlers
-		c.Set("admin_id", id)
-		c.Set("adminId", id)
 		c.Next()
 	}
 }
+
+// sessionClaims parses the bearer token, checks its role and that its session
+// is still active, and sets session_id. It aborts the request and returns
+// false when any check fails.
+func sessionClaims(c *gin.Context, keys *auth.Keys, tokens store.TokenStore, role string) (jwt.MapClaims, bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" || !strings.HasPrefix(header, "Bearer ") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid Authorization header"})
+		c.Abort()
+		return nil, false
+	}
+	tokenStr := strings.TrimPrefix(header, "Bearer ")
+	claims, err := keys.Parse(tokenStr)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return nil, false
+	}
+	if claims["role"] != role {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		c.Abort()
+		return nil, false
+	}
+	sessionID, _ := claims["sid"].(string)
+	if sessionID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token payload"})
+		c.Abort()
+		return nil, false
+	}
+	active, err := tokens.SessionActive(sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check session"})
+		c.Abort()
+		return nil, false
+	}
+	if !active {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has ended, please log in again"})
+		c.Abort()
+		return nil, false
+	}
+	c.Set("session_id", sessionID)
+	return claims, true
+}
@@ -2,45 +2,36 @@ package middleware
 
 import (
 	"net/http"
-	"strings"
 
+	"github.com/Vanaraj10/interior-backend/auth"
+	"github.com/Vanaraj10/interior-backend/store"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-// WorkerAuthMiddleware checks for a valid worker JWT and sets worker_id and admin_id in context
-func WorkerAuthMiddleware() gin.HandlerFunc {
+// WorkerAuthMiddleware checks for a valid worker JWT whose session has not
+// been revoked and whose worker still exists and is active, and sets
+// worker_id, organisation_id and session_id in context
+func WorkerAuthMiddleware(keys *auth.Keys, tokens store.TokenStore, workers store.WorkerStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		header := c.GetHeader("Authorization")
-		if header == "" || !strings.HasPrefix(header, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid Authorization header"})
-			c.Abort()
-			return
-		}
-		tokenStr := strings.TrimPrefix(header, "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return JWTSecret, nil
-		})
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
-		}
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok || claims["role"] != "worker" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
-			c.Abort()
+		claims, ok := sessionClaims(c, keys, tokens, "worker")
+		if !ok {
 			return
 		}
 		workerID, ok := claims["user_id"].(float64) // JWT stores numbers as float64
-		adminID, ok2 := claims["admin_id"].(float64)
+		orgID, ok2 := claims["org_id"].(float64)
 		if !ok || !ok2 {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token payload"})
 			c.Abort()
 			return
 		}
+		// Deleted workers are not returned, so their tokens stop working at once
+		if w, err := workers.GetWorker(int(workerID), int(orgID)); err != nil || !w.IsActive {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Worker account is no longer active"})
+			c.Abort()
+			return
+		}
 		c.Set("worker_id", int(workerID)) // Convert to int for handlers
-		c.Set("admin_id", int(adminID))
+		c.Set("organisation_id", int(orgID))
 		c.Next()
 	}
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/Vanaraj10/interior-backend/migrations"
+)
+
+// runMigrateCommand handles `migrate up|down [steps]|status`
+func runMigrateCommand(db *sql.DB, driver string, args []string) {
+	if len(args) == 0 {
+		fmt.Println("usage: migrate up|down [steps]|status")
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(db, driver)
+		if err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		fmt.Printf("Applied %d migration(s)\n", len(applied))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("migrate down: invalid step count %q", args[1])
+			}
+			steps = n
+		}
+		rolledBack, err := migrations.Down(db, driver, steps)
+		if err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", len(rolledBack))
+	case "status":
+		statuses, err := migrations.CurrentStatus(db, driver)
+		if err != nil {
+			log.Fatalf("migrate status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s %s\n", s.Version, s.Name, state)
+		}
+	default:
+		fmt.Printf("unknown migrate command %q\n", args[0])
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,236 @@
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed mssql/*.sql postgres/*.sql
+var files embed.FS
+
+// dialect holds the bookkeeping SQL of one database. Its migrations are in
+// the directory named after the store driver.
+type dialect struct {
+	trackingTable string
+	record        string
+	forget        string
+}
+
+var dialects = map[string]dialect{
+	"mssql": {
+		trackingTable: `
+IF OBJECT_ID('schema_migrations', 'U') IS NULL
+CREATE TABLE schema_migrations (
+	version INT PRIMARY KEY,
+	name NVARCHAR(255) NOT NULL,
+	applied_at DATETIME NOT NULL
+)
+`,
+		record: `INSERT INTO schema_migrations (version, name, applied_at) VALUES (@p1, @p2, @p3)`,
+		forget: `DELETE FROM schema_migrations WHERE version = @p1`,
+	},
+	"postgres": {
+		trackingTable: `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL
+)
+`,
+		record: `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+		forget: `DELETE FROM schema_migrations WHERE version = $1`,
+	},
+}
+
+// Migration is a numbered schema change with its up and down SQL
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied and when
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Load reads the embedded migration files of a store driver ("mssql" or
+// "postgres"), ordered by version. Files are named
+// NNNN_description.up.sql / NNNN_description.down.sql.
+func Load(driver string) ([]Migration, error) {
+	if _, ok := dialects[driver]; !ok {
+		return nil, fmt.Errorf("no migrations for store driver %q", driver)
+	}
+	entries, err := fs.ReadDir(files, driver)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		name := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		parts := strings.SplitN(base, "_", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		version, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", name, err)
+		}
+		body, err := files.ReadFile(driver + "/" + name)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	var list []Migration
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Up applies every pending migration in order and returns the ones applied
+func Up(db *sql.DB, driver string) ([]Migration, error) {
+	list, applied, err := prepare(db, driver)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, m := range list {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := apply(db, m.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec(dialects[driver].record, m.Version, m.Name, time.Now())
+			return err
+		}); err != nil {
+			return done, fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+		log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Down rolls back the latest applied migrations, up to steps of them
+func Down(db *sql.DB, driver string, steps int) ([]Migration, error) {
+	list, applied, err := prepare(db, driver)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for i := len(list) - 1; i >= 0 && len(done) < steps; i-- {
+		m := list[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if m.Down == "" {
+			return done, fmt.Errorf("migration %04d_%s has no down file", m.Version, m.Name)
+		}
+		if err := apply(db, m.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec(dialects[driver].forget, m.Version)
+			return err
+		}); err != nil {
+			return done, fmt.Errorf("rollback of %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+		log.Printf("Rolled back migration %04d_%s", m.Version, m.Name)
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// CurrentStatus lists every known migration with its applied state
+func CurrentStatus(db *sql.DB, driver string) ([]Status, error) {
+	list, applied, err := prepare(db, driver)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(list))
+	for _, m := range list {
+		s := Status{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &at
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// prepare ensures the tracking table exists and loads migrations plus applied versions
+func prepare(db *sql.DB, driver string) ([]Migration, map[int]time.Time, error) {
+	list, err := Load(driver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := db.Exec(dialects[driver].trackingTable); err != nil {
+		return nil, nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	rows, err := db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, nil, err
+		}
+		applied[version] = at
+	}
+	return list, applied, rows.Err()
+}
+
+// apply runs a migration script and its bookkeeping statement in one transaction
+func apply(db *sql.DB, script string, record func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := record(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
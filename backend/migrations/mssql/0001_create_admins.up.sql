@@ -0,0 +1,7 @@
+IF OBJECT_ID('admins', 'U') IS NULL
+CREATE TABLE admins (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	username NVARCHAR(100) NOT NULL UNIQUE,
+	password_hash NVARCHAR(255) NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT GETDATE()
+);
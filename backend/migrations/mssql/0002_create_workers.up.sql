@@ -0,0 +1,11 @@
+IF OBJECT_ID('workers', 'U') IS NULL
+CREATE TABLE workers (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	username NVARCHAR(100) NOT NULL UNIQUE,
+	password_hash NVARCHAR(255) NOT NULL,
+	admin_id INT NOT NULL,
+	name NVARCHAR(100) NOT NULL DEFAULT '',
+	phone NVARCHAR(20) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT GETDATE(),
+	FOREIGN KEY (admin_id) REFERENCES admins(id)
+);
@@ -0,0 +1,20 @@
+IF OBJECT_ID('projects', 'U') IS NULL
+CREATE TABLE projects (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	client_name NVARCHAR(200) NOT NULL DEFAULT '',
+	phone NVARCHAR(20) NOT NULL DEFAULT '',
+	address NVARCHAR(MAX) NOT NULL DEFAULT '',
+	html NVARCHAR(MAX) NOT NULL DEFAULT '',
+	raw_data NVARCHAR(MAX) NOT NULL DEFAULT '',
+	worker_id INT NOT NULL,
+	admin_id INT NOT NULL,
+	is_completed BIT NOT NULL DEFAULT 0,
+	created_at DATETIME NOT NULL DEFAULT GETDATE(),
+	updated_at DATETIME NOT NULL DEFAULT GETDATE()
+);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_projects_admin_id')
+CREATE INDEX ix_projects_admin_id ON projects (admin_id);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_projects_worker_id')
+CREATE INDEX ix_projects_worker_id ON projects (worker_id);
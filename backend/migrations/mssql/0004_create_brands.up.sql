@@ -0,0 +1,11 @@
+IF OBJECT_ID('brands', 'U') IS NULL
+CREATE TABLE brands (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	name NVARCHAR(100) NOT NULL,
+	description NVARCHAR(MAX),
+	logo_url NVARCHAR(255),
+	admin_id INT NOT NULL,
+	is_active BIT NOT NULL DEFAULT 1,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+);
@@ -0,0 +1,12 @@
+IF OBJECT_ID('folders', 'U') IS NULL
+CREATE TABLE folders (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	name NVARCHAR(100) NOT NULL,
+	description NVARCHAR(MAX),
+	brand_id INT NOT NULL,
+	admin_id INT NOT NULL,
+	is_active BIT NOT NULL DEFAULT 1,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	FOREIGN KEY (brand_id) REFERENCES brands(id)
+);
@@ -0,0 +1,16 @@
+IF OBJECT_ID('cloths', 'U') IS NULL
+CREATE TABLE cloths (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	name NVARCHAR(100) NOT NULL,
+	rate DECIMAL(10, 2) NOT NULL,
+	description NVARCHAR(MAX),
+	image_url NVARCHAR(255),
+	folder_id INT NOT NULL,
+	brand_id INT NOT NULL,
+	admin_id INT NOT NULL,
+	is_active BIT NOT NULL DEFAULT 1,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	FOREIGN KEY (folder_id) REFERENCES folders(id),
+	FOREIGN KEY (brand_id) REFERENCES brands(id)
+);
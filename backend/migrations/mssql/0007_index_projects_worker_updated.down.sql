@@ -0,0 +1 @@
+DROP INDEX IF EXISTS ix_projects_worker_id_updated_at ON projects;
@@ -0,0 +1,2 @@
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_projects_worker_id_updated_at')
+CREATE INDEX ix_projects_worker_id_updated_at ON projects (worker_id, updated_at, id);
@@ -0,0 +1,10 @@
+IF OBJECT_ID('quotation_templates', 'U') IS NULL
+CREATE TABLE quotation_templates (
+	admin_id INT NOT NULL PRIMARY KEY,
+	company_name NVARCHAR(200) NOT NULL DEFAULT '',
+	logo_url NVARCHAR(500) NOT NULL DEFAULT '',
+	notes NVARCHAR(MAX) NULL,
+	stitching_template NVARCHAR(MAX) NOT NULL DEFAULT '',
+	updated_at DATETIME NOT NULL DEFAULT GETDATE(),
+	FOREIGN KEY (admin_id) REFERENCES admins(id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS measurements;
+DROP TABLE IF EXISTS rooms;
@@ -0,0 +1,42 @@
+IF OBJECT_ID('rooms', 'U') IS NULL
+CREATE TABLE rooms (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	project_id INT NOT NULL,
+	room_key NVARCHAR(100) NOT NULL,
+	name NVARCHAR(200) NOT NULL DEFAULT '',
+	type NVARCHAR(50) NOT NULL DEFAULT '',
+	CONSTRAINT uq_rooms_project_room_key UNIQUE (project_id, room_key),
+	FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+IF OBJECT_ID('measurements', 'U') IS NULL
+CREATE TABLE measurements (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	project_id INT NOT NULL,
+	room_id INT NULL,
+	position INT NOT NULL,
+	interior_type NVARCHAR(50) NOT NULL,
+	room_label NVARCHAR(200) NOT NULL DEFAULT '',
+	model NVARCHAR(100) NOT NULL DEFAULT '',
+	width DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	height DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	sqft DECIMAL(12, 4) NOT NULL DEFAULT 0,
+	parts DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	rolls DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	meters DECIMAL(12, 4) NOT NULL DEFAULT 0,
+	material_rate DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	labour_rate DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	material_cost DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	labour_cost DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	hardware_cost DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	total DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+	-- NO ACTION: SQL Server rejects a second cascade path to measurements
+	FOREIGN KEY (room_id) REFERENCES rooms(id)
+);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_measurements_project_id')
+CREATE INDEX ix_measurements_project_id ON measurements (project_id, position);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_measurements_interior_type_model')
+CREATE INDEX ix_measurements_interior_type_model ON measurements (interior_type, model);
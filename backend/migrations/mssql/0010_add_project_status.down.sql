@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS project_status_transitions;
+DROP INDEX IF EXISTS ix_projects_admin_id_status ON projects;
+IF COL_LENGTH('projects', 'status') IS NOT NULL
+BEGIN
+	ALTER TABLE projects DROP CONSTRAINT df_projects_status;
+	ALTER TABLE projects DROP COLUMN status;
+END
@@ -0,0 +1,24 @@
+IF COL_LENGTH('projects', 'status') IS NULL
+ALTER TABLE projects ADD status NVARCHAR(30) NOT NULL CONSTRAINT df_projects_status DEFAULT 'measured';
+
+-- Dynamic SQL: the new column cannot be referenced in the batch that adds it
+EXEC('UPDATE projects SET status = ''closed'' WHERE is_completed = 1 AND status = ''measured''');
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_projects_admin_id_status')
+CREATE INDEX ix_projects_admin_id_status ON projects (admin_id, status);
+
+IF OBJECT_ID('project_status_transitions', 'U') IS NULL
+CREATE TABLE project_status_transitions (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	project_id INT NOT NULL,
+	from_status NVARCHAR(30) NOT NULL,
+	to_status NVARCHAR(30) NOT NULL,
+	actor_role NVARCHAR(20) NOT NULL,
+	actor_id INT NOT NULL,
+	note NVARCHAR(500) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT GETDATE(),
+	FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_project_status_transitions_project_id')
+CREATE INDEX ix_project_status_transitions_project_id ON project_status_transitions (project_id, id);
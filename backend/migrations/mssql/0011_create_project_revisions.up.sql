@@ -0,0 +1,21 @@
+IF OBJECT_ID('project_revisions', 'U') IS NULL
+CREATE TABLE project_revisions (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	project_id INT NOT NULL,
+	revision INT NOT NULL,
+	client_name NVARCHAR(200) NOT NULL DEFAULT '',
+	phone NVARCHAR(20) NOT NULL DEFAULT '',
+	address NVARCHAR(MAX) NOT NULL DEFAULT '',
+	html NVARCHAR(MAX) NOT NULL DEFAULT '',
+	raw_data NVARCHAR(MAX) NOT NULL DEFAULT '',
+	worker_id INT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT GETDATE(),
+	CONSTRAINT uq_project_revisions_project_revision UNIQUE (project_id, revision),
+	FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+-- The saved state of existing projects becomes their first revision
+INSERT INTO project_revisions (project_id, revision, client_name, phone, address, html, raw_data, worker_id, created_at)
+SELECT p.id, 1, p.client_name, p.phone, p.address, p.html, p.raw_data, p.worker_id, p.updated_at
+FROM projects p
+WHERE NOT EXISTS (SELECT 1 FROM project_revisions r WHERE r.project_id = p.id);
@@ -0,0 +1,23 @@
+IF OBJECT_ID('audit_log', 'U') IS NULL
+CREATE TABLE audit_log (
+	id BIGINT IDENTITY(1,1) PRIMARY KEY,
+	admin_id INT NOT NULL,
+	actor_role NVARCHAR(20) NOT NULL,
+	actor_id INT NOT NULL,
+	method NVARCHAR(10) NOT NULL,
+	route NVARCHAR(200) NOT NULL,
+	path NVARCHAR(500) NOT NULL,
+	entity_type NVARCHAR(50) NOT NULL DEFAULT '',
+	entity_id INT NULL,
+	status INT NOT NULL,
+	before_json NVARCHAR(MAX) NULL,
+	after_json NVARCHAR(MAX) NULL,
+	client_ip NVARCHAR(64) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT GETDATE()
+);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_audit_log_admin_id_created_at')
+CREATE INDEX ix_audit_log_admin_id_created_at ON audit_log (admin_id, created_at);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_audit_log_admin_id_entity')
+CREATE INDEX ix_audit_log_admin_id_entity ON audit_log (admin_id, entity_type, entity_id);
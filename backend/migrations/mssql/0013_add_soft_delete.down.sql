@@ -0,0 +1,7 @@
+-- Rows that were soft deleted are removed for good
+EXEC('DELETE FROM projects WHERE deleted_at IS NOT NULL');
+IF COL_LENGTH('projects', 'deleted_at') IS NOT NULL
+ALTER TABLE projects DROP COLUMN deleted_at;
+
+IF COL_LENGTH('workers', 'deleted_at') IS NOT NULL
+ALTER TABLE workers DROP COLUMN deleted_at;
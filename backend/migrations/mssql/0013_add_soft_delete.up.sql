@@ -0,0 +1,5 @@
+IF COL_LENGTH('projects', 'deleted_at') IS NULL
+ALTER TABLE projects ADD deleted_at DATETIME NULL;
+
+IF COL_LENGTH('workers', 'deleted_at') IS NULL
+ALTER TABLE workers ADD deleted_at DATETIME NULL;
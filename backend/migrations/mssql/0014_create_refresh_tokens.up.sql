@@ -0,0 +1,19 @@
+IF OBJECT_ID('refresh_tokens', 'U') IS NULL
+CREATE TABLE refresh_tokens (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	token_hash CHAR(64) NOT NULL,
+	session_id CHAR(32) NOT NULL,
+	role NVARCHAR(20) NOT NULL,
+	user_id INT NOT NULL,
+	admin_id INT NOT NULL,
+	expires_at DATETIME NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT GETDATE(),
+	revoked_at DATETIME NULL,
+	CONSTRAINT uq_refresh_tokens_token_hash UNIQUE (token_hash)
+);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_refresh_tokens_session_id')
+CREATE INDEX ix_refresh_tokens_session_id ON refresh_tokens (session_id);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_refresh_tokens_role_user_id')
+CREATE INDEX ix_refresh_tokens_role_user_id ON refresh_tokens (role, user_id);
@@ -0,0 +1,14 @@
+IF OBJECT_ID('failed_logins', 'U') IS NULL
+CREATE TABLE failed_logins (
+	id BIGINT IDENTITY(1,1) PRIMARY KEY,
+	admin_id INT NULL,
+	role NVARCHAR(20) NOT NULL,
+	username NVARCHAR(100) NOT NULL,
+	user_id INT NULL,
+	reason NVARCHAR(20) NOT NULL,
+	client_ip NVARCHAR(64) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT GETDATE()
+);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_failed_logins_admin_id_created_at')
+CREATE INDEX ix_failed_logins_admin_id_created_at ON failed_logins (admin_id, created_at);
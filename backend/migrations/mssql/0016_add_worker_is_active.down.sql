@@ -0,0 +1,5 @@
+IF COL_LENGTH('workers', 'is_active') IS NOT NULL
+BEGIN
+	ALTER TABLE workers DROP CONSTRAINT df_workers_is_active;
+	ALTER TABLE workers DROP COLUMN is_active;
+END
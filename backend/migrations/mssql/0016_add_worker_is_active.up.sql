@@ -0,0 +1,2 @@
+IF COL_LENGTH('workers', 'is_active') IS NULL
+ALTER TABLE workers ADD is_active BIT NOT NULL CONSTRAINT df_workers_is_active DEFAULT 1;
@@ -0,0 +1,46 @@
+-- Organisations are folded back into their first admin. Tenant rows of an
+-- organisation are handed to that admin, and admins who are not the first of
+-- their organisation are removed.
+ALTER TABLE workers DROP CONSTRAINT IF EXISTS fk_workers_organisation_id;
+ALTER TABLE quotation_templates DROP CONSTRAINT IF EXISTS fk_quotation_templates_organisation_id;
+
+EXEC sp_rename 'workers.organisation_id', 'admin_id', 'COLUMN';
+EXEC sp_rename 'projects.organisation_id', 'admin_id', 'COLUMN';
+EXEC sp_rename 'brands.organisation_id', 'admin_id', 'COLUMN';
+EXEC sp_rename 'folders.organisation_id', 'admin_id', 'COLUMN';
+EXEC sp_rename 'cloths.organisation_id', 'admin_id', 'COLUMN';
+EXEC sp_rename 'quotation_templates.organisation_id', 'admin_id', 'COLUMN';
+EXEC sp_rename 'audit_log.organisation_id', 'admin_id', 'COLUMN';
+EXEC sp_rename 'refresh_tokens.organisation_id', 'admin_id', 'COLUMN';
+EXEC sp_rename 'failed_logins.organisation_id', 'admin_id', 'COLUMN';
+
+EXEC sp_rename 'projects.ix_projects_organisation_id', 'ix_projects_admin_id', 'INDEX';
+EXEC sp_rename 'projects.ix_projects_organisation_id_status', 'ix_projects_admin_id_status', 'INDEX';
+EXEC sp_rename 'audit_log.ix_audit_log_organisation_id_created_at', 'ix_audit_log_admin_id_created_at', 'INDEX';
+EXEC sp_rename 'audit_log.ix_audit_log_organisation_id_entity', 'ix_audit_log_admin_id_entity', 'INDEX';
+EXEC sp_rename 'failed_logins.ix_failed_logins_organisation_id_created_at', 'ix_failed_logins_admin_id_created_at', 'INDEX';
+
+DECLARE @tables TABLE (name SYSNAME);
+INSERT INTO @tables VALUES ('workers'), ('projects'), ('brands'), ('folders'), ('cloths'), ('quotation_templates'), ('audit_log'), ('refresh_tokens'), ('failed_logins');
+DECLARE @sql NVARCHAR(MAX) = N'';
+SELECT @sql += N'UPDATE t SET admin_id = o.first_admin FROM ' + QUOTENAME(name) + N' t JOIN (SELECT organisation_id, MIN(id) AS first_admin FROM admins GROUP BY organisation_id) o ON o.organisation_id = t.admin_id;'
+FROM @tables;
+EXEC sp_executesql @sql;
+
+EXEC('DELETE FROM refresh_tokens WHERE role = ''admin'' AND user_id NOT IN (SELECT MIN(id) FROM admins GROUP BY organisation_id)');
+EXEC('DELETE FROM admins WHERE id NOT IN (SELECT MIN(id) FROM admins GROUP BY organisation_id)');
+
+ALTER TABLE admins DROP CONSTRAINT IF EXISTS fk_admins_organisation_id;
+DROP INDEX IF EXISTS ix_admins_organisation_id ON admins;
+IF COL_LENGTH('admins', 'organisation_id') IS NOT NULL
+ALTER TABLE admins DROP COLUMN organisation_id;
+IF COL_LENGTH('admins', 'role') IS NOT NULL
+BEGIN
+	ALTER TABLE admins DROP CONSTRAINT df_admins_role;
+	ALTER TABLE admins DROP COLUMN role;
+END
+
+DROP TABLE IF EXISTS organisations;
+
+EXEC('ALTER TABLE workers ADD FOREIGN KEY (admin_id) REFERENCES admins(id)');
+EXEC('ALTER TABLE quotation_templates ADD FOREIGN KEY (admin_id) REFERENCES admins(id)');
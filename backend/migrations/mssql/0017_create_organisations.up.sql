@@ -0,0 +1,49 @@
+IF OBJECT_ID('organisations', 'U') IS NULL
+CREATE TABLE organisations (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	name NVARCHAR(200) NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT GETDATE()
+);
+
+-- Every existing admin becomes the owner of an organisation with the same id,
+-- so the admin ids stored on tenant rows are valid organisation ids as they are
+SET IDENTITY_INSERT organisations ON;
+INSERT INTO organisations (id, name, created_at)
+SELECT a.id, a.username, a.created_at FROM admins a WHERE NOT EXISTS (SELECT 1 FROM organisations o WHERE o.id = a.id);
+SET IDENTITY_INSERT organisations OFF;
+
+IF COL_LENGTH('admins', 'organisation_id') IS NULL
+ALTER TABLE admins ADD organisation_id INT NULL;
+
+IF COL_LENGTH('admins', 'role') IS NULL
+ALTER TABLE admins ADD role NVARCHAR(20) NOT NULL CONSTRAINT df_admins_role DEFAULT 'owner';
+
+EXEC('UPDATE admins SET organisation_id = id WHERE organisation_id IS NULL');
+EXEC('ALTER TABLE admins ALTER COLUMN organisation_id INT NOT NULL');
+EXEC('ALTER TABLE admins ADD CONSTRAINT fk_admins_organisation_id FOREIGN KEY (organisation_id) REFERENCES organisations(id)');
+EXEC('CREATE INDEX ix_admins_organisation_id ON admins (organisation_id)');
+
+-- Tenant rows now belong to the organisation rather than to one admin
+DECLARE @sql NVARCHAR(MAX) = N'';
+SELECT @sql += N'ALTER TABLE ' + QUOTENAME(OBJECT_NAME(parent_object_id)) + N' DROP CONSTRAINT ' + QUOTENAME(name) + N';'
+FROM sys.foreign_keys WHERE referenced_object_id = OBJECT_ID('admins') AND name <> 'fk_admins_organisation_id';
+EXEC sp_executesql @sql;
+
+EXEC sp_rename 'workers.admin_id', 'organisation_id', 'COLUMN';
+EXEC sp_rename 'projects.admin_id', 'organisation_id', 'COLUMN';
+EXEC sp_rename 'brands.admin_id', 'organisation_id', 'COLUMN';
+EXEC sp_rename 'folders.admin_id', 'organisation_id', 'COLUMN';
+EXEC sp_rename 'cloths.admin_id', 'organisation_id', 'COLUMN';
+EXEC sp_rename 'quotation_templates.admin_id', 'organisation_id', 'COLUMN';
+EXEC sp_rename 'audit_log.admin_id', 'organisation_id', 'COLUMN';
+EXEC sp_rename 'refresh_tokens.admin_id', 'organisation_id', 'COLUMN';
+EXEC sp_rename 'failed_logins.admin_id', 'organisation_id', 'COLUMN';
+
+EXEC sp_rename 'projects.ix_projects_admin_id', 'ix_projects_organisation_id', 'INDEX';
+EXEC sp_rename 'projects.ix_projects_admin_id_status', 'ix_projects_organisation_id_status', 'INDEX';
+EXEC sp_rename 'audit_log.ix_audit_log_admin_id_created_at', 'ix_audit_log_organisation_id_created_at', 'INDEX';
+EXEC sp_rename 'audit_log.ix_audit_log_admin_id_entity', 'ix_audit_log_organisation_id_entity', 'INDEX';
+EXEC sp_rename 'failed_logins.ix_failed_logins_admin_id_created_at', 'ix_failed_logins_organisation_id_created_at', 'INDEX';
+
+EXEC('ALTER TABLE workers ADD CONSTRAINT fk_workers_organisation_id FOREIGN KEY (organisation_id) REFERENCES organisations(id)');
+EXEC('ALTER TABLE quotation_templates ADD CONSTRAINT fk_quotation_templates_organisation_id FOREIGN KEY (organisation_id) REFERENCES organisations(id)');
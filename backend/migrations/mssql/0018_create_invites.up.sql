@@ -0,0 +1,11 @@
+IF OBJECT_ID('invites', 'U') IS NULL
+CREATE TABLE invites (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	code_hash CHAR(64) NOT NULL,
+	note NVARCHAR(255) NOT NULL DEFAULT '',
+	expires_at DATETIME NOT NULL,
+	used_at DATETIME NULL,
+	organisation_id INT NULL REFERENCES organisations(id),
+	created_at DATETIME NOT NULL DEFAULT GETDATE(),
+	CONSTRAINT uq_invites_code_hash UNIQUE (code_hash)
+);
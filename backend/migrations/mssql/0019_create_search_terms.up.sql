@@ -0,0 +1,15 @@
+IF OBJECT_ID('search_terms', 'U') IS NULL
+CREATE TABLE search_terms (
+	project_id INT NOT NULL,
+	organisation_id INT NOT NULL,
+	field NVARCHAR(20) NOT NULL,
+	term NVARCHAR(100) NOT NULL,
+	weight INT NOT NULL,
+	FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_search_terms_organisation_id_term')
+CREATE INDEX ix_search_terms_organisation_id_term ON search_terms (organisation_id, term) INCLUDE (project_id, weight);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_search_terms_project_id')
+CREATE INDEX ix_search_terms_project_id ON search_terms (project_id);
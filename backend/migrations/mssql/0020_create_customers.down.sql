@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS ix_projects_customer_id ON projects;
+IF COL_LENGTH('projects', 'customer_id') IS NOT NULL
+BEGIN
+	ALTER TABLE projects DROP CONSTRAINT fk_projects_customer_id;
+	ALTER TABLE projects DROP COLUMN customer_id;
+END
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,25 @@
+IF OBJECT_ID('customers', 'U') IS NULL
+CREATE TABLE customers (
+	id INT IDENTITY(1,1) PRIMARY KEY,
+	organisation_id INT NOT NULL,
+	name NVARCHAR(200) NOT NULL,
+	phone NVARCHAR(20) NOT NULL,
+	normalized_phone NVARCHAR(20) NOT NULL,
+	address NVARCHAR(MAX) NOT NULL DEFAULT '',
+	merged_into_id INT NULL,
+	created_at DATETIME NOT NULL DEFAULT GETDATE(),
+	updated_at DATETIME NOT NULL DEFAULT GETDATE(),
+	CONSTRAINT fk_customers_organisation_id FOREIGN KEY (organisation_id) REFERENCES organisations(id),
+	CONSTRAINT fk_customers_merged_into_id FOREIGN KEY (merged_into_id) REFERENCES customers(id)
+);
+
+-- Merged customers keep their row so their phone still finds the customer
+-- they were merged into
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ux_customers_organisation_id_normalized_phone')
+CREATE UNIQUE INDEX ux_customers_organisation_id_normalized_phone ON customers (organisation_id, normalized_phone);
+
+IF COL_LENGTH('projects', 'customer_id') IS NULL
+ALTER TABLE projects ADD customer_id INT NULL CONSTRAINT fk_projects_customer_id REFERENCES customers(id);
+
+IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ix_projects_customer_id')
+EXEC('CREATE INDEX ix_projects_customer_id ON projects (customer_id)');
@@ -0,0 +1,4 @@
+-- isCompleted follows the status now; projects marked completed by the old
+-- toggle without reaching a completed status are closed, as in 0010
+UPDATE projects SET status = 'closed', updated_at = GETDATE()
+WHERE is_completed = 1 AND status NOT IN ('installed', 'closed');
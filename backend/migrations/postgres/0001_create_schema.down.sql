@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS failed_logins;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS quotation_templates;
+DROP TABLE IF EXISTS cloths;
+DROP TABLE IF EXISTS folders;
+DROP TABLE IF EXISTS brands;
+DROP TABLE IF EXISTS measurements;
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS project_revisions;
+DROP TABLE IF EXISTS project_status_transitions;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS workers;
+DROP TABLE IF EXISTS invites;
+DROP TABLE IF EXISTS admins;
+DROP TABLE IF EXISTS organisations;
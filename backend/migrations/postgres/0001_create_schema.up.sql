@@ -0,0 +1,211 @@
+-- The PostgreSQL schema starts from the state the SQL Server schema reached in
+-- its migration 0018; later changes get their own migrations here.
+
+CREATE TABLE IF NOT EXISTS organisations (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(200) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS admins (
+	id SERIAL PRIMARY KEY,
+	username VARCHAR(100) NOT NULL UNIQUE,
+	password_hash VARCHAR(255) NOT NULL,
+	organisation_id INT NOT NULL REFERENCES organisations(id),
+	role VARCHAR(20) NOT NULL DEFAULT 'owner',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ix_admins_organisation_id ON admins (organisation_id);
+
+CREATE TABLE IF NOT EXISTS invites (
+	id SERIAL PRIMARY KEY,
+	code_hash CHAR(64) NOT NULL,
+	note VARCHAR(255) NOT NULL DEFAULT '',
+	expires_at TIMESTAMPTZ NOT NULL,
+	used_at TIMESTAMPTZ NULL,
+	organisation_id INT NULL REFERENCES organisations(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	CONSTRAINT uq_invites_code_hash UNIQUE (code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS workers (
+	id SERIAL PRIMARY KEY,
+	username VARCHAR(100) NOT NULL UNIQUE,
+	password_hash VARCHAR(255) NOT NULL,
+	organisation_id INT NOT NULL REFERENCES organisations(id),
+	name VARCHAR(100) NOT NULL DEFAULT '',
+	phone VARCHAR(20) NOT NULL DEFAULT '',
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMPTZ NULL
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+	id SERIAL PRIMARY KEY,
+	client_name VARCHAR(200) NOT NULL DEFAULT '',
+	phone VARCHAR(20) NOT NULL DEFAULT '',
+	address TEXT NOT NULL DEFAULT '',
+	html TEXT NOT NULL DEFAULT '',
+	raw_data TEXT NOT NULL DEFAULT '',
+	worker_id INT NOT NULL,
+	organisation_id INT NOT NULL,
+	is_completed BOOLEAN NOT NULL DEFAULT FALSE,
+	status VARCHAR(30) NOT NULL DEFAULT 'measured',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS ix_projects_organisation_id ON projects (organisation_id);
+CREATE INDEX IF NOT EXISTS ix_projects_worker_id ON projects (worker_id);
+CREATE INDEX IF NOT EXISTS ix_projects_worker_id_updated_at ON projects (worker_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS ix_projects_organisation_id_status ON projects (organisation_id, status);
+
+CREATE TABLE IF NOT EXISTS project_status_transitions (
+	id SERIAL PRIMARY KEY,
+	project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	from_status VARCHAR(30) NOT NULL,
+	to_status VARCHAR(30) NOT NULL,
+	actor_role VARCHAR(20) NOT NULL,
+	actor_id INT NOT NULL,
+	note VARCHAR(500) NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ix_project_status_transitions_project_id ON project_status_transitions (project_id, id);
+
+CREATE TABLE IF NOT EXISTS project_revisions (
+	id SERIAL PRIMARY KEY,
+	project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	revision INT NOT NULL,
+	client_name VARCHAR(200) NOT NULL DEFAULT '',
+	phone VARCHAR(20) NOT NULL DEFAULT '',
+	address TEXT NOT NULL DEFAULT '',
+	html TEXT NOT NULL DEFAULT '',
+	raw_data TEXT NOT NULL DEFAULT '',
+	worker_id INT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	CONSTRAINT uq_project_revisions_project_revision UNIQUE (project_id, revision)
+);
+
+CREATE TABLE IF NOT EXISTS rooms (
+	id SERIAL PRIMARY KEY,
+	project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	room_key VARCHAR(100) NOT NULL,
+	name VARCHAR(200) NOT NULL DEFAULT '',
+	type VARCHAR(50) NOT NULL DEFAULT '',
+	CONSTRAINT uq_rooms_project_room_key UNIQUE (project_id, room_key)
+);
+
+CREATE TABLE IF NOT EXISTS measurements (
+	id SERIAL PRIMARY KEY,
+	project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	room_id INT NULL REFERENCES rooms(id),
+	position INT NOT NULL,
+	interior_type VARCHAR(50) NOT NULL,
+	room_label VARCHAR(200) NOT NULL DEFAULT '',
+	model VARCHAR(100) NOT NULL DEFAULT '',
+	width DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	height DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	sqft DECIMAL(12, 4) NOT NULL DEFAULT 0,
+	parts DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	rolls DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	meters DECIMAL(12, 4) NOT NULL DEFAULT 0,
+	material_rate DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	labour_rate DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	material_cost DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	labour_cost DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	hardware_cost DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	total DECIMAL(12, 2) NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS ix_measurements_project_id ON measurements (project_id, position);
+CREATE INDEX IF NOT EXISTS ix_measurements_interior_type_model ON measurements (interior_type, model);
+
+CREATE TABLE IF NOT EXISTS brands (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	description TEXT,
+	logo_url VARCHAR(255),
+	organisation_id INT NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS folders (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	description TEXT,
+	brand_id INT NOT NULL REFERENCES brands(id),
+	organisation_id INT NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS cloths (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	rate DECIMAL(10, 2) NOT NULL,
+	description TEXT,
+	image_url VARCHAR(255),
+	folder_id INT NOT NULL REFERENCES folders(id),
+	brand_id INT NOT NULL REFERENCES brands(id),
+	organisation_id INT NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS quotation_templates (
+	organisation_id INT NOT NULL PRIMARY KEY REFERENCES organisations(id),
+	company_name VARCHAR(200) NOT NULL DEFAULT '',
+	logo_url VARCHAR(500) NOT NULL DEFAULT '',
+	notes TEXT NULL,
+	stitching_template TEXT NOT NULL DEFAULT '',
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+	id BIGSERIAL PRIMARY KEY,
+	organisation_id INT NOT NULL,
+	actor_role VARCHAR(20) NOT NULL,
+	actor_id INT NOT NULL,
+	method VARCHAR(10) NOT NULL,
+	route VARCHAR(200) NOT NULL,
+	path VARCHAR(500) NOT NULL,
+	entity_type VARCHAR(50) NOT NULL DEFAULT '',
+	entity_id INT NULL,
+	status INT NOT NULL,
+	before_json TEXT NULL,
+	after_json TEXT NULL,
+	client_ip VARCHAR(64) NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ix_audit_log_organisation_id_created_at ON audit_log (organisation_id, created_at);
+CREATE INDEX IF NOT EXISTS ix_audit_log_organisation_id_entity ON audit_log (organisation_id, entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id SERIAL PRIMARY KEY,
+	token_hash CHAR(64) NOT NULL,
+	session_id CHAR(32) NOT NULL,
+	role VARCHAR(20) NOT NULL,
+	user_id INT NOT NULL,
+	organisation_id INT NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	revoked_at TIMESTAMPTZ NULL,
+	CONSTRAINT uq_refresh_tokens_token_hash UNIQUE (token_hash)
+);
+CREATE INDEX IF NOT EXISTS ix_refresh_tokens_session_id ON refresh_tokens (session_id);
+CREATE INDEX IF NOT EXISTS ix_refresh_tokens_role_user_id ON refresh_tokens (role, user_id);
+
+CREATE TABLE IF NOT EXISTS failed_logins (
+	id BIGSERIAL PRIMARY KEY,
+	organisation_id INT NULL,
+	role VARCHAR(20) NOT NULL,
+	username VARCHAR(100) NOT NULL,
+	user_id INT NULL,
+	reason VARCHAR(20) NOT NULL,
+	client_ip VARCHAR(64) NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ix_failed_logins_organisation_id_created_at ON failed_logins (organisation_id, created_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS search_terms (
+	project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	organisation_id INT NOT NULL,
+	field VARCHAR(20) NOT NULL,
+	term VARCHAR(100) NOT NULL,
+	weight INT NOT NULL
+);
+
+-- varchar_pattern_ops lets prefix LIKE searches use the index in any collation
+CREATE INDEX IF NOT EXISTS ix_search_terms_organisation_id_term ON search_terms (organisation_id, term varchar_pattern_ops);
+CREATE INDEX IF NOT EXISTS ix_search_terms_project_id ON search_terms (project_id);
@@ -0,0 +1,2 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS customer_id;
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS customers (
+	id SERIAL PRIMARY KEY,
+	organisation_id INT NOT NULL REFERENCES organisations(id),
+	name VARCHAR(200) NOT NULL,
+	phone VARCHAR(20) NOT NULL,
+	normalized_phone VARCHAR(20) NOT NULL,
+	address TEXT NOT NULL DEFAULT '',
+	merged_into_id INT NULL REFERENCES customers(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Merged customers keep their row so their phone still finds the customer
+-- they were merged into
+CREATE UNIQUE INDEX IF NOT EXISTS ux_customers_organisation_id_normalized_phone ON customers (organisation_id, normalized_phone);
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS customer_id INT NULL REFERENCES customers(id);
+CREATE INDEX IF NOT EXISTS ix_projects_customer_id ON projects (customer_id);
@@ -0,0 +1,2 @@
+-- The previous status of the closed projects is not kept, so they stay closed
+SELECT 1;
@@ -0,0 +1,5 @@
+-- isCompleted follows the status now; projects marked completed by the old
+-- toggle without reaching a completed status are closed, as in the SQL
+-- Server migration that added the status
+UPDATE projects SET status = 'closed', updated_at = CURRENT_TIMESTAMP
+WHERE is_completed AND status NOT IN ('installed', 'closed');
@@ -1,75 +1,284 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
+// Organisation is a shop. Its admins share its workers, projects and catalog.
+type Organisation struct {
+	ID        int       `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Admin is a person managing an organisation. Role decides what they may do;
+// see the access package.
 type Admin struct {
-	ID           int       `db:"id" json:"id"`
-	Username     string    `db:"username" json:"username"`
-	PasswordHash string    `db:"password_hash" json:"-"`
-	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	ID             int       `db:"id" json:"id"`
+	Username       string    `db:"username" json:"username"`
+	PasswordHash   string    `db:"password_hash" json:"-"`
+	OrganisationID int       `db:"organisation_id" json:"organisationId"`
+	Role           string    `db:"role" json:"role"`
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Invite lets someone sign up a new organisation. Only a hash of its code is
+// stored; OrganisationID is the organisation created with it, once used.
+type Invite struct {
+	ID             int        `db:"id" json:"id"`
+	CodeHash       string     `db:"code_hash" json:"-"`
+	Note           string     `db:"note" json:"note"`
+	ExpiresAt      time.Time  `db:"expires_at" json:"expiresAt"`
+	UsedAt         *time.Time `db:"used_at" json:"usedAt"`
+	OrganisationID *int       `db:"organisation_id" json:"organisationId"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
 }
 
 type Worker struct {
-	ID           int       `db:"id" json:"id"`
-	Username     string    `db:"username" json:"username"`
-	PasswordHash string    `db:"password_hash" json:"-"`
-	AdminID      int       `db:"admin_id" json:"adminId"`
-	Name         string    `db:"name" json:"name"`
-	Phone        string    `db:"phone" json:"phone"`
-	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	ID             int        `db:"id" json:"id"`
+	Username       string     `db:"username" json:"username"`
+	PasswordHash   string     `db:"password_hash" json:"-"`
+	OrganisationID int        `db:"organisation_id" json:"organisationId"`
+	Name           string     `db:"name" json:"name"`
+	Phone          string     `db:"phone" json:"phone"`
+	IsActive       bool       `db:"is_active" json:"isActive"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	DeletedAt      *time.Time `db:"deleted_at" json:"deletedAt"`
+}
+
+// RefreshToken is a stored refresh token. Only a hash of the token is kept.
+// The tokens issued from one login share a SessionID; a token is revoked when
+// it is exchanged for the next one, or when its session ends.
+type RefreshToken struct {
+	ID             int        `db:"id" json:"id"`
+	TokenHash      string     `db:"token_hash" json:"-"`
+	SessionID      string     `db:"session_id" json:"sessionId"`
+	Role           string     `db:"role" json:"role"`
+	UserID         int        `db:"user_id" json:"userId"`
+	OrganisationID int        `db:"organisation_id" json:"organisationId"`
+	ExpiresAt      time.Time  `db:"expires_at" json:"expiresAt"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	RevokedAt      *time.Time `db:"revoked_at" json:"revokedAt"`
 }
 
 type Project struct {
-	ID          int       `db:"id" json:"id"`
-	ClientName  string    `db:"client_name" json:"clientName"`
-	Phone       string    `db:"phone" json:"phone"`
-	Address     string    `db:"address" json:"address"`
-	HTML        string    `db:"html" json:"html"`
-	RawData     string    `db:"raw_data" json:"rawData"`
-	WorkerID    int       `db:"worker_id" json:"workerId"`
-	AdminID     int       `db:"admin_id" json:"adminId"`
-	IsCompleted bool      `db:"is_completed" json:"isCompleted"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
-}
-
-// Brand represents a cloth brand that can be managed by an admin
+	ID             int        `db:"id" json:"id"`
+	ClientName     string     `db:"client_name" json:"clientName"`
+	Phone          string     `db:"phone" json:"phone"`
+	Address        string     `db:"address" json:"address"`
+	HTML           string     `db:"html" json:"html"`
+	RawData        string     `db:"raw_data" json:"rawData"`
+	WorkerID       int        `db:"worker_id" json:"workerId"`
+	OrganisationID int        `db:"organisation_id" json:"organisationId"`
+	CustomerID     *int       `db:"customer_id" json:"customerId"`
+	IsCompleted    bool       `db:"is_completed" json:"isCompleted"`
+	Status         string     `db:"status" json:"status"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updatedAt"`
+	DeletedAt      *time.Time `db:"deleted_at" json:"deletedAt"`
+}
+
+// ProjectSummary is a project as admins list it: without its html and
+// rawData, but with Total, the quotation grand total of its rawData when it
+// can be priced
+type ProjectSummary struct {
+	ID             int        `json:"id"`
+	ClientName     string     `json:"clientName"`
+	Phone          string     `json:"phone"`
+	Address        string     `json:"address"`
+	WorkerID       int        `json:"workerId"`
+	OrganisationID int        `json:"organisationId"`
+	CustomerID     *int       `json:"customerId"`
+	IsCompleted    bool       `json:"isCompleted"`
+	Status         string     `json:"status"`
+	Total          *float64   `json:"total"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+	DeletedAt      *time.Time `json:"deletedAt"`
+}
+
+// StatusTransition records one change of a project's status and who made it.
+// ActorRole is "admin" or "worker" and ActorID the admin or worker id.
+type StatusTransition struct {
+	ID         int       `db:"id" json:"id"`
+	ProjectID  int       `db:"project_id" json:"projectId"`
+	FromStatus string    `db:"from_status" json:"fromStatus"`
+	ToStatus   string    `db:"to_status" json:"toStatus"`
+	ActorRole  string    `db:"actor_role" json:"actorRole"`
+	ActorID    int       `db:"actor_id" json:"actorId"`
+	Note       string    `db:"note" json:"note"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Brand represents a cloth brand of an organisation's catalog
 type Brand struct {
-	ID          int       `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	LogoURL     string    `db:"logo_url" json:"logoUrl"`
-	AdminID     int       `db:"admin_id" json:"adminId"`
-	IsActive    bool      `db:"is_active" json:"isActive"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+	ID             int       `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	Description    string    `db:"description" json:"description"`
+	LogoURL        string    `db:"logo_url" json:"logoUrl"`
+	OrganisationID int       `db:"organisation_id" json:"organisationId"`
+	IsActive       bool      `db:"is_active" json:"isActive"`
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updatedAt"`
 }
 
 // Folder represents a folder within a brand that contains cloths
 type Folder struct {
-	ID          int       `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	BrandID     int       `db:"brand_id" json:"brandId"`
-	AdminID     int       `db:"admin_id" json:"adminId"`
-	IsActive    bool      `db:"is_active" json:"isActive"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+	ID             int       `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	Description    string    `db:"description" json:"description"`
+	BrandID        int       `db:"brand_id" json:"brandId"`
+	OrganisationID int       `db:"organisation_id" json:"organisationId"`
+	IsActive       bool      `db:"is_active" json:"isActive"`
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updatedAt"`
 }
 
 // Cloth represents a cloth item that belongs to a folder
 type Cloth struct {
-	ID          int       `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Rate        float64   `db:"rate" json:"rate"`
-	Description string    `db:"description" json:"description"`
-	ImageURL    string    `db:"image_url" json:"imageUrl"`
-	FolderID    int       `db:"folder_id" json:"folderId"`
-	BrandID     int       `db:"brand_id" json:"brandId"`
-	AdminID     int       `db:"admin_id" json:"adminId"`
-	IsActive    bool      `db:"is_active" json:"isActive"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+	ID             int       `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	Rate           float64   `db:"rate" json:"rate"`
+	Description    string    `db:"description" json:"description"`
+	ImageURL       string    `db:"image_url" json:"imageUrl"`
+	FolderID       int       `db:"folder_id" json:"folderId"`
+	BrandID        int       `db:"brand_id" json:"brandId"`
+	OrganisationID int       `db:"organisation_id" json:"organisationId"`
+	IsActive       bool      `db:"is_active" json:"isActive"`
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// FolderWithBrand is a folder joined with its brand's name
+type FolderWithBrand struct {
+	Folder
+	BrandName string `json:"brandName"`
+}
+
+// ClothWithDetails is a cloth joined with its folder and brand names
+type ClothWithDetails struct {
+	Cloth
+	FolderName string `json:"folderName"`
+	BrandName  string `json:"brandName"`
+}
+
+// QuotationTemplate is an organisation's branding and optional custom stitching sheet template
+type QuotationTemplate struct {
+	OrganisationID    int       `db:"organisation_id" json:"organisationId"`
+	CompanyName       string    `db:"company_name" json:"companyName"`
+	LogoURL           string    `db:"logo_url" json:"logoUrl"`
+	Notes             []string  `db:"notes" json:"notes"`
+	StitchingTemplate string    `db:"stitching_template" json:"stitchingTemplate"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// Customer is a client of an organisation, identified by their normalized
+// phone number. Name, Phone and Address are those of their latest saved
+// project. A customer merged into another keeps its row, with MergedIntoID
+// set, so projects saved with its phone go to the other customer.
+type Customer struct {
+	ID              int       `db:"id" json:"id"`
+	OrganisationID  int       `db:"organisation_id" json:"organisationId"`
+	Name            string    `db:"name" json:"name"`
+	Phone           string    `db:"phone" json:"phone"`
+	NormalizedPhone string    `db:"normalized_phone" json:"normalizedPhone"`
+	Address         string    `db:"address" json:"address"`
+	MergedIntoID    *int      `db:"merged_into_id" json:"mergedIntoId,omitempty"`
+	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// SearchTerm is a word of one of a project's fields in the search index,
+// with the weight a match on it scores
+type SearchTerm struct {
+	Field  string `db:"field" json:"field"`
+	Term   string `db:"term" json:"term"`
+	Weight int    `db:"weight" json:"weight"`
+}
+
+// Room is a room of a project, normalized from its rawData. RoomKey is the
+// room id the app assigned.
+type Room struct {
+	ID        int    `db:"id" json:"id"`
+	ProjectID int    `db:"project_id" json:"projectId"`
+	RoomKey   string `db:"room_key" json:"roomKey"`
+	Name      string `db:"name" json:"name"`
+	Type      string `db:"type" json:"type"`
+}
+
+// Measurement is one measured item of a project, normalized from its rawData
+// with server-computed prices. Costs exclude GST and the room-level rod cost.
+type Measurement struct {
+	ID           int     `db:"id" json:"id"`
+	ProjectID    int     `db:"project_id" json:"projectId"`
+	RoomID       *int    `db:"room_id" json:"roomId"`
+	RoomKey      string  `db:"-" json:"roomKey"`
+	Position     int     `db:"position" json:"position"`
+	InteriorType string  `db:"interior_type" json:"interiorType"`
+	RoomLabel    string  `db:"room_label" json:"roomLabel"`
+	Model        string  `db:"model" json:"model"`
+	Width        float64 `db:"width" json:"width"`
+	Height       float64 `db:"height" json:"height"`
+	Sqft         float64 `db:"sqft" json:"sqft"`
+	Parts        float64 `db:"parts" json:"parts"`
+	Rolls        float64 `db:"rolls" json:"rolls"`
+	Meters       float64 `db:"meters" json:"meters"`
+	MaterialRate float64 `db:"material_rate" json:"materialRate"`
+	LabourRate   float64 `db:"labour_rate" json:"labourRate"`
+	MaterialCost float64 `db:"material_cost" json:"materialCost"`
+	LabourCost   float64 `db:"labour_cost" json:"labourCost"`
+	HardwareCost float64 `db:"hardware_cost" json:"hardwareCost"`
+	Total        float64 `db:"total" json:"total"`
+}
+
+// ProjectRevision is an immutable copy of a project as a worker saved it.
+// Revisions are numbered from 1 per project. Total is the quotation grand
+// total of its rawData, computed when the revision is read.
+type ProjectRevision struct {
+	ID         int       `db:"id" json:"id"`
+	ProjectID  int       `db:"project_id" json:"projectId"`
+	Revision   int       `db:"revision" json:"revision"`
+	ClientName string    `db:"client_name" json:"clientName"`
+	Phone      string    `db:"phone" json:"phone"`
+	Address    string    `db:"address" json:"address"`
+	HTML       string    `db:"html" json:"html,omitempty"`
+	RawData    string    `db:"raw_data" json:"rawData,omitempty"`
+	WorkerID   int       `db:"worker_id" json:"workerId"`
+	Total      *float64  `db:"-" json:"total"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
+
+// AuditEntry records one mutating API request. OrganisationID is the
+// organisation the actor works for, so each organisation sees its own activity. Before and
+// After are JSON snapshots of the entity, when it could be loaded.
+type AuditEntry struct {
+	ID             int64           `db:"id" json:"id"`
+	OrganisationID int             `db:"organisation_id" json:"organisationId"`
+	ActorRole      string          `db:"actor_role" json:"actorRole"`
+	ActorID        int             `db:"actor_id" json:"actorId"`
+	Method         string          `db:"method" json:"method"`
+	Route          string          `db:"route" json:"route"`
+	Path           string          `db:"path" json:"path"`
+	EntityType     string          `db:"entity_type" json:"entityType"`
+	EntityID       *int            `db:"entity_id" json:"entityId"`
+	Status         int             `db:"status" json:"status"`
+	Before         json.RawMessage `db:"before_json" json:"before"`
+	After          json.RawMessage `db:"after_json" json:"after"`
+	ClientIP       string          `db:"client_ip" json:"clientIp"`
+	CreatedAt      time.Time       `db:"created_at" json:"createdAt"`
+}
+
+// FailedLogin records a rejected login attempt. OrganisationID is the
+// organisation the account belongs to and UserID the account, both unset when the username
+// does not exist. Reason is bad_password, unknown_user or disabled.
+type FailedLogin struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganisationID *int      `db:"organisation_id" json:"organisationId"`
+	Role           string    `db:"role" json:"role"`
+	Username       string    `db:"username" json:"username"`
+	UserID         *int      `db:"user_id" json:"userId"`
+	Reason         string    `db:"reason" json:"reason"`
+	ClientIP       string    `db:"client_ip" json:"clientIp"`
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
 }
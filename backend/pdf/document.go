@@ -0,0 +1,279 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/pricing"
+	"github.com/go-pdf/fpdf"
+)
+
+// Company is the letterhead printed at the top of every document
+type Company struct {
+	Name    string
+	Tagline string
+}
+
+// DefaultCompany matches the letterhead of the quotations generated on the phone
+var DefaultCompany = Company{Name: "BENTLEI CURTAIN", Tagline: "Designed With Luxury & Comfort"}
+
+// Options selects the documents included in the PDF
+type Options struct {
+	Company   Company
+	Quotation bool // customer quotation with prices
+	Stitching bool // stitching-unit sheet, curtains only
+	Notes     []string
+}
+
+const (
+	margin     = 10.0
+	rowHeight  = 7.0
+	fontFamily = "Helvetica"
+)
+
+var (
+	brandColor = [3]int{37, 99, 235}   // #2563eb, as in the HTML quotations
+	roomColor  = [3]int{240, 249, 255} // #f0f9ff
+	totalColor = [3]int{240, 240, 240}
+	mutedColor = [3]int{102, 102, 102}
+)
+
+// Render writes the selected documents for a project as a single PDF
+func Render(w io.Writer, p models.Project, q *pricing.ProjectQuotation, opts Options) error {
+	if opts.Company.Name == "" {
+		opts.Company = DefaultCompany
+	}
+	d := newDocument(p, opts.Company)
+	if opts.Quotation {
+		d.quotation(q)
+	}
+	if opts.Stitching && len(q.Curtains.Rooms) > 0 {
+		d.stitchingSheet(q.Curtains, opts.Notes)
+	}
+	return d.pdf.Output(w)
+}
+
+// document wraps the PDF being built with the project it describes
+type document struct {
+	pdf     *fpdf.Fpdf
+	tr      func(string) string
+	project models.Project
+	company Company
+}
+
+func newDocument(p models.Project, company Company) *document {
+	f := fpdf.New("L", "mm", "A4", "")
+	f.SetMargins(margin, margin, margin)
+	f.SetAutoPageBreak(true, margin+5)
+	f.SetTitle(fmt.Sprintf("Quotation - %s", p.ClientName), true)
+	f.SetAuthor(company.Name, true)
+	f.AliasNbPages("")
+	d := &document{pdf: f, tr: f.UnicodeTranslatorFromDescriptor(""), project: p, company: company}
+	f.SetFooterFunc(func() {
+		f.SetY(-margin - 2)
+		d.font("", 8, mutedColor)
+		f.CellFormat(0, 5, d.tr(fmt.Sprintf("Project #%d - Page %d of {nb}", p.ID, f.PageNo())), "", 0, "C", false, 0, "")
+	})
+	return d
+}
+
+// width is the printable width of the page
+func (d *document) width() float64 {
+	w, _ := d.pdf.GetPageSize()
+	return w - 2*margin
+}
+
+func (d *document) font(style string, size float64, color [3]int) {
+	d.pdf.SetFont(fontFamily, style, size)
+	d.pdf.SetTextColor(color[0], color[1], color[2])
+}
+
+// letterhead starts a new page with the company band and the document title
+func (d *document) letterhead(title, subtitle string) {
+	f := d.pdf
+	f.AddPage()
+	f.SetFillColor(brandColor[0], brandColor[1], brandColor[2])
+	d.font("B", 18, [3]int{255, 255, 255})
+	f.CellFormat(0, 11, d.tr(d.company.Name), "", 1, "C", true, 0, "")
+	d.font("", 10, [3]int{255, 255, 255})
+	f.CellFormat(0, 6, d.tr(d.company.Tagline), "", 1, "C", true, 0, "")
+	f.Ln(4)
+	d.font("B", 14, brandColor)
+	f.CellFormat(0, 8, d.tr(title), "", 1, "C", false, 0, "")
+	if subtitle != "" {
+		d.font("", 10, mutedColor)
+		f.CellFormat(0, 5, d.tr(subtitle), "", 1, "C", false, 0, "")
+	}
+	f.Ln(3)
+	d.clientInfo()
+}
+
+func (d *document) clientInfo() {
+	p := d.project
+	half := d.width() / 2
+	rows := [][2]string{
+		{"Client Name: " + p.ClientName, "Date: " + p.CreatedAt.Format("02-01-2006")},
+		{"Phone: " + p.Phone, fmt.Sprintf("Project ID: #%d", p.ID)},
+		{"Address: " + p.Address, ""},
+	}
+	d.font("", 10, [3]int{51, 51, 51})
+	for _, r := range rows {
+		d.pdf.CellFormat(half, 6, d.fit(r[0], half), "B", 0, "L", false, 0, "")
+		d.pdf.CellFormat(half, 6, d.fit(r[1], half), "B", 1, "R", false, 0, "")
+	}
+	d.pdf.Ln(4)
+}
+
+func (d *document) sectionTitle(title string) {
+	if d.pdf.GetY()+4*rowHeight > d.pageBottom() {
+		d.pdf.AddPage()
+	}
+	d.pdf.Ln(2)
+	d.font("B", 12, brandColor)
+	d.pdf.CellFormat(0, 8, d.tr(title), "B", 1, "L", false, 0, "")
+	d.pdf.Ln(2)
+}
+
+func (d *document) paragraph(text string) {
+	d.font("", 9, mutedColor)
+	d.pdf.MultiCell(0, 5, d.tr(text), "", "L", false)
+}
+
+func (d *document) pageBottom() float64 {
+	_, h := d.pdf.GetPageSize()
+	return h - margin - 5
+}
+
+// fit translates s for the core fonts and shortens it to fit in width
+func (d *document) fit(s string, width float64) string {
+	s = d.tr(s)
+	max := width - 2*d.pdf.GetCellMargin()
+	if d.pdf.GetStringWidth(s) <= max {
+		return s
+	}
+	for len(s) > 0 && d.pdf.GetStringWidth(s+"...") > max {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
+
+// column describes a table column; widths are relative and scaled to the page
+type column struct {
+	title  string
+	weight float64
+	align  string
+}
+
+type table struct {
+	d      *document
+	cols   []column
+	widths []float64
+}
+
+// newTable draws the header row of a table that fills the page width
+func (d *document) newTable(cols ...column) *table {
+	var total float64
+	for _, c := range cols {
+		total += c.weight
+	}
+	t := &table{d: d, cols: cols}
+	for _, c := range cols {
+		t.widths = append(t.widths, c.weight/total*d.width())
+	}
+	t.header()
+	return t
+}
+
+func (t *table) header() {
+	f := t.d.pdf
+	f.SetFillColor(brandColor[0], brandColor[1], brandColor[2])
+	f.SetDrawColor(221, 221, 221)
+	t.d.font("B", 9, [3]int{255, 255, 255})
+	for i, c := range t.cols {
+		f.CellFormat(t.widths[i], rowHeight, t.d.fit(c.title, t.widths[i]), "1", 0, "C", true, 0, "")
+	}
+	f.Ln(-1)
+}
+
+// ensureSpace moves to a new page, repeating the header, when the next row would not fit
+func (t *table) ensureSpace() {
+	if t.d.pdf.GetY()+rowHeight > t.d.pageBottom() {
+		t.d.pdf.AddPage()
+		t.header()
+	}
+}
+
+func (t *table) row(cells ...string) {
+	t.cells("", nil, cells)
+}
+
+func (t *table) totalRow(cells ...string) {
+	t.cells("B", &totalColor, cells)
+}
+
+func (t *table) cells(style string, fill *[3]int, cells []string) {
+	t.ensureSpace()
+	f := t.d.pdf
+	if fill != nil {
+		f.SetFillColor(fill[0], fill[1], fill[2])
+	}
+	t.d.font(style, 9, [3]int{51, 51, 51})
+	for i, c := range t.cols {
+		text := ""
+		if i < len(cells) {
+			text = cells[i]
+		}
+		f.CellFormat(t.widths[i], rowHeight, t.d.fit(text, t.widths[i]), "1", 0, c.align, fill != nil, 0, "")
+	}
+	f.Ln(-1)
+}
+
+// band draws a full-width shaded row, used for room headings
+func (t *table) band(text string) {
+	t.ensureSpace()
+	t.d.pdf.SetFillColor(roomColor[0], roomColor[1], roomColor[2])
+	t.d.font("B", 9, [3]int{51, 51, 51})
+	t.d.pdf.CellFormat(t.d.width(), rowHeight, t.d.fit(text, t.d.width()), "1", 1, "L", true, 0, "")
+}
+
+// money formats rupees with Indian digit grouping, e.g. Rs. 1,23,456
+func money(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	cents := math.Round(v * 100)
+	whole, paise := math.Floor(cents/100), math.Mod(cents, 100)
+	digits := strconv.FormatFloat(whole, 'f', 0, 64)
+	if len(digits) > 3 {
+		head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+		var groups []string
+		for len(head) > 2 {
+			groups = append([]string{head[len(head)-2:]}, groups...)
+			head = head[:len(head)-2]
+		}
+		groups = append([]string{head}, groups...)
+		digits = strings.Join(groups, ",") + "," + tail
+	}
+	if paise > 0 {
+		digits += fmt.Sprintf(".%02.0f", paise)
+	}
+	return "Rs. " + sign + digits
+}
+
+// num formats a measurement without trailing zeros
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func inches(v float64) string {
+	return num(v) + `"`
+}
+
+func fixed(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
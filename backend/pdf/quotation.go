@@ -0,0 +1,176 @@
+package pdf
+
+import (
+	"fmt"
+
+	"github.com/Vanaraj10/interior-backend/pricing"
+)
+
+// itemSection lays out the table for one non-curtain interior type
+type itemSection struct {
+	interiorType string
+	title        string
+	columns      []column
+	cells        func(b pricing.ItemBreakdown) []string
+}
+
+var itemSections = []itemSection{
+	{
+		interiorType: pricing.MosquitoNets,
+		title:        "Mosquito Nets",
+		columns: []column{
+			{"#", 3, "C"}, {"Item", 16, "L"}, {"Width", 7, "C"}, {"Height", 7, "C"},
+			{"Material", 12, "C"}, {"Sqft", 7, "C"}, {"Total", 10, "R"},
+		},
+		cells: func(b pricing.ItemBreakdown) []string {
+			return []string{b.RoomLabel, inches(b.Width), inches(b.Height), b.Kind, fixed(b.Sqft), money(b.Total)}
+		},
+	},
+	{
+		interiorType: pricing.Wallpapers,
+		title:        "Wallpapers",
+		columns: []column{
+			{"#", 3, "C"}, {"Item", 16, "L"}, {"Width", 7, "C"}, {"Height", 7, "C"}, {"Sqft", 7, "C"},
+			{"Rolls", 6, "C"}, {"Material", 10, "R"}, {"Implementation", 10, "R"}, {"Total", 10, "R"},
+		},
+		cells: func(b pricing.ItemBreakdown) []string {
+			return []string{b.RoomLabel, inches(b.Width), inches(b.Height), fixed(b.Sqft), num(b.Quantity),
+				money(b.MaterialCost), money(b.ExtraCost), money(b.Total)}
+		},
+	},
+	{
+		interiorType: pricing.Blinds,
+		title:        "Blinds",
+		columns: []column{
+			{"#", 3, "C"}, {"Item", 16, "L"}, {"Width", 7, "C"}, {"Height", 7, "C"}, {"Type", 11, "C"},
+			{"Sqft", 7, "C"}, {"Blind Cost", 10, "R"}, {"Cloth & Stitching", 11, "R"}, {"Total", 10, "R"},
+		},
+		cells: func(b pricing.ItemBreakdown) []string {
+			extra := "-"
+			if b.Quantity > 0 {
+				extra = money(b.ExtraCost)
+			}
+			return []string{b.RoomLabel, inches(b.Width), inches(b.Height), b.Kind, fixed(b.Sqft),
+				money(b.MaterialCost), extra, money(b.Total)}
+		},
+	},
+	{
+		interiorType: pricing.Flooring,
+		title:        "Flooring",
+		columns: []column{
+			{"#", 3, "C"}, {"Item", 16, "L"}, {"Width", 7, "C"}, {"Height", 7, "C"}, {"Sqft", 7, "C"},
+			{"Material", 10, "R"}, {"Laying", 10, "R"}, {"Total", 10, "R"},
+		},
+		cells: func(b pricing.ItemBreakdown) []string {
+			return []string{b.RoomLabel, inches(b.Width), inches(b.Height), fixed(b.Sqft),
+				money(b.MaterialCost), money(b.ExtraCost), money(b.Total)}
+		},
+	},
+}
+
+// quotation renders the customer quotation with prices for every interior type
+func (d *document) quotation(q *pricing.ProjectQuotation) {
+	d.letterhead("QUOTATION", "")
+	if len(q.Curtains.Rooms) > 0 {
+		d.curtainTables(q.Curtains)
+	}
+	for _, s := range itemSections {
+		d.itemTable(s, q.Items)
+	}
+	d.costSummary(q)
+	d.pdf.Ln(4)
+	d.paragraph("Thank you for your business. For any questions, please contact us.")
+}
+
+func (d *document) curtainTables(c *pricing.Quotation) {
+	d.sectionTitle("Curtains")
+	t := d.newTable(
+		column{"#", 3, "C"}, column{"Item", 14, "L"}, column{"Width", 6, "C"}, column{"Height", 6, "C"},
+		column{"Parts", 5, "C"}, column{"Stitching Model", 11, "C"}, column{"Cloth", 7, "C"},
+		column{"Cloth Cost", 9, "R"}, column{"Stitching", 9, "R"}, column{"Lining", 7, "C"},
+		column{"Lining Cost", 9, "R"}, column{"Total", 10, "R"},
+	)
+	n := 1
+	var parts float64
+	for _, room := range c.Rooms {
+		t.band("Room: " + room.RoomName)
+		for _, m := range room.Measurements {
+			lining, liningCost := "-", "-"
+			if m.HasLining {
+				lining, liningCost = fixed(m.Meters)+"m", money(m.LiningCost)
+			}
+			t.row(fmt.Sprint(n), m.RoomLabel, inches(m.Width), inches(m.Height), num(m.Parts),
+				m.StitchingModel, fixed(m.Meters)+"m", money(m.ClothCost), money(m.StitchingCost),
+				lining, liningCost, money(m.TotalCurtainCost))
+			parts += m.Parts
+			n++
+		}
+	}
+	t.totalRow("", "Total", "", "", num(parts), "", "", "", "", "", "", money(c.CurtainCost))
+
+	d.sectionTitle("Rods & Wall Brackets")
+	t = d.newTable(
+		column{"#", 3, "C"}, column{"Item", 14, "L"}, column{"Bracket Model", 12, "C"},
+		column{"Rod Length", 8, "C"}, column{"Clamps", 9, "R"}, column{"Dooms", 9, "R"}, column{"Wall Brackets", 10, "R"},
+	)
+	n = 1
+	for _, room := range c.Rooms {
+		t.band(fmt.Sprintf("Room: %s - %d rod(s) x %s = %s", room.RoomName, room.RodsRequired, money(room.RodRate), money(room.RodCost)))
+		for _, m := range room.Measurements {
+			bracket := m.BracketModel
+			if bracket == "" {
+				bracket = "-"
+			}
+			t.row(fmt.Sprint(n), m.RoomLabel, bracket, fixed(m.Width/12)+" ft",
+				money(m.ClampCost), money(m.DoomCost), money(m.WallBracketCost))
+			n++
+		}
+	}
+	t.totalRow("", "Total", fmt.Sprintf("%d rod(s)", c.RodsRequired), "", "", "Rods: "+money(c.RodCost), money(c.WallBracketCost))
+
+	d.sectionTitle("Curtain Cost Summary")
+	t = d.newTable(column{"Description", 3, "L"}, column{"Amount", 1, "R"})
+	t.row("Total Rods Required", fmt.Sprintf("%d rods", c.RodsRequired))
+	t.row("Rod Calculation Cost", money(c.RodCost))
+	t.row("Total Wall Brackets Cost", money(c.WallBracketCost))
+	t.row(fmt.Sprintf("Cloth Cost (with %.0f%% GST)", pricing.ClothGSTRate*100), money(c.ClothCostWithGST))
+	t.row(fmt.Sprintf("Rod Cost (with %.0f%% GST)", pricing.RodGSTRate*100), money(c.RodCostWithGST))
+	t.totalRow("Curtains Total", money(c.GrandTotal))
+}
+
+func (d *document) itemTable(s itemSection, items []pricing.ItemBreakdown) {
+	var rows []pricing.ItemBreakdown
+	for _, b := range items {
+		if b.InteriorType == s.interiorType {
+			rows = append(rows, b)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	d.sectionTitle(s.title)
+	t := d.newTable(s.columns...)
+	var total float64
+	for i, b := range rows {
+		t.row(append([]string{fmt.Sprint(i + 1)}, s.cells(b)...)...)
+		total += b.Total
+	}
+	totals := make([]string, len(s.columns))
+	totals[1] = "Total"
+	totals[len(totals)-1] = money(total)
+	t.totalRow(totals...)
+}
+
+func (d *document) costSummary(q *pricing.ProjectQuotation) {
+	d.sectionTitle("Cost Summary")
+	t := d.newTable(column{"Description", 3, "L"}, column{"Amount", 1, "R"})
+	if total, ok := q.Subtotals["curtains"]; ok {
+		t.row("Curtains Subtotal", money(total))
+	}
+	for _, s := range itemSections {
+		if total, ok := q.Subtotals[s.interiorType]; ok {
+			t.row(s.title+" Subtotal", money(total))
+		}
+	}
+	t.totalRow("GRAND TOTAL", money(q.GrandTotal))
+}
@@ -0,0 +1,38 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Vanaraj10/interior-backend/pricing"
+)
+
+// stitchingSheet renders the curtain details the stitching unit needs, without prices
+func (d *document) stitchingSheet(c *pricing.Quotation, notes []string) {
+	d.letterhead("STITCHING UNIT QUOTATION", "Technical Specifications for Production")
+	d.sectionTitle("Curtain Measurements & Stitching Details")
+	t := d.newTable(
+		column{"#", 3, "C"}, column{"Item/Location", 16, "L"}, column{"Width", 7, "C"}, column{"Height", 7, "C"},
+		column{"Parts", 6, "C"}, column{"Stitching Model", 12, "C"}, column{"Special Instructions", 24, "L"},
+	)
+	for _, room := range c.Rooms {
+		t.band("Room: " + room.RoomName)
+		for i, m := range room.Measurements {
+			t.row(fmt.Sprint(i+1), m.RoomLabel, inches(m.Width), inches(m.Height),
+				num(m.Parts)+" parts", m.StitchingModel, specialInstructions(m))
+		}
+	}
+
+	if len(notes) > 0 {
+		d.sectionTitle("Important Notes for Stitching Unit")
+		d.paragraph("- " + strings.Join(notes, "\n- "))
+	}
+}
+
+func specialInstructions(m pricing.MeasurementBreakdown) string {
+	lines := m.Instructions()
+	if len(lines) == 0 {
+		return "-"
+	}
+	return strings.Join(lines, "; ")
+}
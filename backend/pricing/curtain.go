@@ -0,0 +1,365 @@
+package pricing
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Vanaraj10/interior-backend/projectdata"
+)
+
+const (
+	// HemAllowance is the extra cloth (inches) added to the height for hems and
+	// headers, unless a measurement records the allowance it was priced with
+	HemAllowance = 15.0
+	// MetreDivisor converts inches of cloth across the rounded parts into metres
+	MetreDivisor = 39.0
+	// RodLength is the standard rod length in inches used for rod packing
+	RodLength = 144.0
+	// ClothGSTRate applies to cloth, stitching and lining
+	ClothGSTRate = 0.05
+	// RodGSTRate applies to rods, clamps and dooms
+	RodGSTRate = 0.18
+	// Tolerance is the allowed difference (in rupees) before a submitted value is flagged
+	Tolerance = 1.0
+)
+
+// CurtainInput holds the rate and size fields read from a curtain measurement.
+// A nil HemAllowance means the default HemAllowance; zero means none.
+type CurtainInput struct {
+	RoomID               string
+	RoomName             string
+	RoomLabel            string
+	StitchingModel       string
+	Opening              string
+	BracketModel         string
+	LiningModel          string
+	Width                float64
+	Height               float64
+	HemAllowance         *float64
+	ClothRatePerMeter    float64
+	StitchingCostPerPart float64
+	HasLining            bool
+	LiningRatePerMeter   float64
+	RodRatePerLength     float64
+	ClampRequired        float64
+	ClampRatePerPiece    float64
+	DoomRequired         float64
+	DoomRatePerPiece     float64
+}
+
+// MeasurementBreakdown is the recomputed pricing of a single curtain measurement
+type MeasurementBreakdown struct {
+	Index            int     `json:"index"`
+	RoomID           string  `json:"roomId"`
+	RoomLabel        string  `json:"roomLabel"`
+	StitchingModel   string  `json:"stitchingModel"`
+	Opening          string  `json:"opening,omitempty"`
+	BracketModel     string  `json:"bracketModel,omitempty"`
+	HasLining        bool    `json:"hasLining"`
+	LiningModel      string  `json:"liningModel,omitempty"`
+	Width            float64 `json:"width"`
+	Height           float64 `json:"height"`
+	Parts            float64 `json:"parts"`
+	RoundedParts     float64 `json:"roundedParts"`
+	Meters           float64 `json:"meters"`
+	ClothCost        float64 `json:"clothCost"`
+	StitchingCost    float64 `json:"stitchingCost"`
+	LiningCost       float64 `json:"liningCost"`
+	TotalCurtainCost float64 `json:"totalCurtainCost"`
+	ClampCost        float64 `json:"clampCost"`
+	DoomCost         float64 `json:"doomCost"`
+	WallBracketCost  float64 `json:"wallBracketCost"`
+}
+
+// RoomBreakdown groups measurements of one room together with the room's rod cost
+type RoomBreakdown struct {
+	RoomID           string                 `json:"roomId"`
+	RoomName         string                 `json:"roomName"`
+	Measurements     []MeasurementBreakdown `json:"measurements"`
+	CurtainCost      float64                `json:"curtainCost"`
+	RodsRequired     int                    `json:"rodsRequired"`
+	RodRate          float64                `json:"rodRate"`
+	RodCost          float64                `json:"rodCost"`
+	WallBracketCost  float64                `json:"wallBracketCost"`
+	ClothCostWithGST float64                `json:"clothCostWithGST"`
+	RodCostWithGST   float64                `json:"rodCostWithGST"`
+	Total            float64                `json:"total"`
+}
+
+// Mismatch records a submitted value that differs from the recomputed one
+type Mismatch struct {
+	Index     int     `json:"index"`
+	RoomLabel string  `json:"roomLabel"`
+	Field     string  `json:"field"`
+	Submitted float64 `json:"submitted"`
+	Computed  float64 `json:"computed"`
+}
+
+// Quotation is the full curtain pricing breakdown for a project
+type Quotation struct {
+	Rooms            []RoomBreakdown `json:"rooms"`
+	CurtainCost      float64         `json:"curtainCost"`
+	RodsRequired     int             `json:"rodsRequired"`
+	RodCost          float64         `json:"rodCost"`
+	WallBracketCost  float64         `json:"wallBracketCost"`
+	ClothCostWithGST float64         `json:"clothCostWithGST"`
+	RodCostWithGST   float64         `json:"rodCostWithGST"`
+	GrandTotal       float64         `json:"grandTotal"`
+	Mismatches       []Mismatch      `json:"mismatches"`
+	Matches          bool            `json:"matches"`
+}
+
+// PartsFromWidth returns the number of curtain parts for a width in inches
+func PartsFromWidth(width float64) float64 {
+	switch {
+	case width <= 20:
+		return 1.0
+	case width <= 28:
+		return 1.5
+	case width <= 40:
+		return 2.0
+	case width <= 50:
+		return 2.5
+	case width <= 60:
+		return 3.0
+	case width <= 70:
+		return 3.5
+	case width <= 80:
+		return 4.0
+	case width <= 90:
+		return 4.5
+	case width <= 100:
+		return 5.0
+	case width <= 110:
+		return 5.5
+	case width <= 120:
+		return 6.0
+	case width <= 130:
+		return 6.5
+	case width <= 140:
+		return 7.0
+	default:
+		return 7 + math.Ceil((width-140)/10)*0.5
+	}
+}
+
+// ClothMeters returns the cloth required for a height and hem allowance in
+// inches and a parts count
+func ClothMeters(height, hemAllowance, parts float64) float64 {
+	return ((height + hemAllowance) * math.Ceil(parts)) / MetreDivisor
+}
+
+// RodsRequired packs the widths onto standard rods, largest first, and returns the rod count
+func RodsRequired(widths []float64) int {
+	sorted := append([]float64{}, widths...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+	var rods []float64
+	for _, w := range sorted {
+		placed := false
+		for i := range rods {
+			if rods[i]+w <= RodLength {
+				rods[i] += w
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			rods = append(rods, w)
+		}
+	}
+	return len(rods)
+}
+
+// PriceMeasurement computes the per-measurement costs of a curtain
+func PriceMeasurement(in CurtainInput) MeasurementBreakdown {
+	parts := PartsFromWidth(in.Width)
+	hem := HemAllowance
+	if in.HemAllowance != nil {
+		hem = *in.HemAllowance
+	}
+	meters := ClothMeters(in.Height, hem, parts)
+	b := MeasurementBreakdown{
+		RoomID:         in.RoomID,
+		RoomLabel:      in.RoomLabel,
+		StitchingModel: in.StitchingModel,
+		Opening:        in.Opening,
+		BracketModel:   in.BracketModel,
+		HasLining:      in.HasLining,
+		Width:          in.Width,
+		Height:         in.Height,
+		Parts:          parts,
+		RoundedParts:   math.Ceil(parts),
+		Meters:         meters,
+		ClothCost:      math.Ceil(meters * in.ClothRatePerMeter),
+		StitchingCost:  math.Ceil(parts * in.StitchingCostPerPart),
+		ClampCost:      math.Ceil(in.ClampRequired * in.ClampRatePerPiece),
+		DoomCost:       math.Ceil(in.DoomRequired * in.DoomRatePerPiece),
+	}
+	if in.HasLining {
+		b.LiningModel = in.LiningModel
+		b.LiningCost = math.Ceil(meters * in.LiningRatePerMeter)
+	}
+	b.TotalCurtainCost = b.ClothCost + b.StitchingCost + b.LiningCost
+	b.WallBracketCost = b.ClampCost + b.DoomCost
+	return b
+}
+
+// Instructions lists the opening and lining notes for the stitching unit
+func (b MeasurementBreakdown) Instructions() []string {
+	var lines []string
+	if b.Opening != "" {
+		lines = append(lines, "Opening: "+b.Opening)
+	}
+	if b.HasLining {
+		lining := "With Lining"
+		if b.LiningModel != "" {
+			lining += " (" + b.LiningModel + ")"
+		}
+		lines = append(lines, lining)
+	}
+	return lines
+}
+
+// QuoteRawData parses a project's rawData JSON and recomputes the curtain quotation
+func QuoteRawData(rawData string) (*Quotation, error) {
+	p, err := projectdata.ParseString(rawData)
+	if err != nil {
+		return nil, err
+	}
+	return quoteCurtains(p), nil
+}
+
+// quoteCurtains prices the curtain measurements and compares them with the submitted values
+func quoteCurtains(p *projectdata.Project) *Quotation {
+	curtains := p.Curtains()
+	inputs := make([]CurtainInput, len(curtains))
+	for i, c := range curtains {
+		inputs[i] = curtainInput(c)
+		if inputs[i].RoomName == "" {
+			inputs[i].RoomName = p.RoomName(c.RoomID)
+		}
+	}
+
+	q := Quote(inputs)
+	q.Mismatches = compareSubmitted(q, curtains)
+	q.Matches = len(q.Mismatches) == 0
+	return q
+}
+
+// Quote prices a list of curtain measurements, grouping rod costs by room
+func Quote(inputs []CurtainInput) *Quotation {
+	q := &Quotation{Rooms: []RoomBreakdown{}, Mismatches: []Mismatch{}}
+	roomIndex := make(map[string]int)
+	var widths [][]float64
+
+	for i, in := range inputs {
+		b := PriceMeasurement(in)
+		b.Index = i
+		idx, exists := roomIndex[in.RoomID]
+		if !exists {
+			idx = len(q.Rooms)
+			roomIndex[in.RoomID] = idx
+			name := in.RoomName
+			if name == "" {
+				name = "Other Measurements"
+			}
+			// The first measurement of a room sets the rod rate, as in the mobile quotation
+			q.Rooms = append(q.Rooms, RoomBreakdown{RoomID: in.RoomID, RoomName: name, RodRate: in.RodRatePerLength})
+			widths = append(widths, nil)
+		}
+		room := &q.Rooms[idx]
+		room.Measurements = append(room.Measurements, b)
+		room.CurtainCost += b.TotalCurtainCost
+		room.WallBracketCost += b.WallBracketCost
+		widths[idx] = append(widths[idx], in.Width)
+	}
+
+	var rodCost float64
+	for i := range q.Rooms {
+		room := &q.Rooms[i]
+		room.RodsRequired = RodsRequired(widths[i])
+		room.RodCost = float64(room.RodsRequired) * room.RodRate
+		room.ClothCostWithGST = math.Ceil(room.CurtainCost * (1 + ClothGSTRate))
+		room.RodCostWithGST = math.Ceil((room.WallBracketCost + math.Ceil(room.RodCost)) * (1 + RodGSTRate))
+		room.Total = room.ClothCostWithGST + room.RodCostWithGST
+
+		q.CurtainCost += room.CurtainCost
+		q.RodsRequired += room.RodsRequired
+		q.WallBracketCost += room.WallBracketCost
+		rodCost += room.RodCost
+	}
+
+	// Project totals apply GST once on the summed costs, matching the quotation summary
+	q.RodCost = math.Ceil(rodCost)
+	q.ClothCostWithGST = math.Ceil(q.CurtainCost * (1 + ClothGSTRate))
+	q.RodCostWithGST = math.Ceil((q.WallBracketCost + q.RodCost) * (1 + RodGSTRate))
+	q.GrandTotal = q.ClothCostWithGST + q.RodCostWithGST
+	return q
+}
+
+// compareSubmitted checks the totals the worker submitted against the recomputed breakdown
+func compareSubmitted(q *Quotation, submitted []*projectdata.Curtain) []Mismatch {
+	mismatches := []Mismatch{}
+	for _, room := range q.Rooms {
+		for _, b := range room.Measurements {
+			m := submitted[b.Index]
+			fields := []struct {
+				name      string
+				submitted *projectdata.Number
+				computed  float64
+			}{
+				{"clothCost", m.ClothCost, b.ClothCost},
+				{"stitchingCost", m.StitchingCost, b.StitchingCost},
+				{"liningCost", m.LiningCost, b.LiningCost},
+				{"totalCurtainCost", m.TotalCurtainCost, b.TotalCurtainCost},
+				{"clampCost", m.ClampCost, b.ClampCost},
+				{"doomCost", m.DoomCost, b.DoomCost},
+			}
+			for _, f := range fields {
+				if f.submitted == nil {
+					continue
+				}
+				value := f.submitted.Float()
+				if math.Abs(value-f.computed) > Tolerance {
+					mismatches = append(mismatches, Mismatch{
+						Index:     b.Index,
+						RoomLabel: b.RoomLabel,
+						Field:     f.name,
+						Submitted: value,
+						Computed:  f.computed,
+					})
+				}
+			}
+		}
+	}
+	return mismatches
+}
+
+// curtainInput reads the rate and size fields of a curtain measurement
+func curtainInput(c *projectdata.Curtain) CurtainInput {
+	in := CurtainInput{
+		RoomID:               c.RoomID,
+		RoomName:             c.RoomName,
+		RoomLabel:            c.RoomLabel,
+		StitchingModel:       c.StitchingModel,
+		Opening:              c.Opening,
+		BracketModel:         c.BracketModel,
+		LiningModel:          c.LiningModel,
+		Width:                c.Width.Float(),
+		Height:               c.Height.Float(),
+		ClothRatePerMeter:    c.ClothRatePerMeter.Float(),
+		StitchingCostPerPart: c.StitchingCostPerPart.Float(),
+		HasLining:            c.HasLining,
+		LiningRatePerMeter:   c.LiningRatePerMeter.Float(),
+		RodRatePerLength:     c.RodRatePerLength.Float(),
+		ClampRequired:        c.ClampRequired.Float(),
+		ClampRatePerPiece:    c.ClampRatePerPiece.Float(),
+		DoomRequired:         c.DoomRequired.Float(),
+		DoomRatePerPiece:     c.DoomRatePerPiece.Float(),
+	}
+	if c.HemAllowance != nil {
+		hem := c.HemAllowance.Float()
+		in.HemAllowance = &hem
+	}
+	return in
+}
@@ -0,0 +1,130 @@
+package pricing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Vanaraj10/interior-backend/projectdata"
+)
+
+// hallCurtain is a 60" x 84" curtain: 3 parts, cloth at 300, stitching at 250
+// a part, 4 clamps at 50 and 2 dooms at 30 on a 600 rod. extra is spliced in
+// after the rates, e.g. a hem allowance and the app's own costs.
+func hallCurtain(extra string) string {
+	return `{"interiorType": "curtains", "roomId": "r1", "roomName": "Hall", "roomLabel": "Window",
+		"width": 60, "height": 84, "stitchingModel": "Pleated", "curtainBracketModels": "MS Rod",
+		"clothRatePerMeter": 300, "stitchingCostPerPart": 250, "rodRatePerLength": 600,
+		"clampRequired": 4, "clampRatePerPiece": 50, "doomRequired": 2, "doomRatePerPiece": 30` + extra + `}`
+}
+
+func TestPartsFromWidth(t *testing.T) {
+	tests := []struct {
+		width, parts float64
+	}{
+		{10, 1}, {20, 1}, {21, 1.5}, {40, 2}, {60, 3}, {140, 7}, {141, 7.5}, {150, 7.5}, {151, 8},
+	}
+	for _, tt := range tests {
+		if got := PartsFromWidth(tt.width); got != tt.parts {
+			t.Errorf("PartsFromWidth(%v) = %v, want %v", tt.width, got, tt.parts)
+		}
+	}
+}
+
+func TestQuoteProject(t *testing.T) {
+	tests := []struct {
+		name       string
+		rawData    string
+		clothCost  float64
+		grandTotal float64
+		matches    bool
+	}{
+		{
+			// (84 + 15) * 3 / 39 metres at 300 is 2284.6; with 750 stitching
+			// the curtain is 3035, and 260 of clamps and dooms on one 600 rod
+			name:       "hem allowance sent by the app",
+			rawData:    `{"version": 1, "measurements": [` + hallCurtain(`, "opening": "Single Open", "hemAllowance": 15, "clothCost": 2285, "totalCurtainCost": 3035`) + `]}`,
+			clothCost:  2285,
+			grandTotal: 3187 + 1015,
+			matches:    true,
+		},
+		{
+			// Older apps priced with 12: (84 + 12) * 3 / 39 metres at 300
+			name:       "legacy payload without a hem allowance",
+			rawData:    `{"measurements": [` + hallCurtain(`, "clothCost": 2216, "totalCurtainCost": 2966`) + `]}`,
+			clothCost:  2216,
+			grandTotal: 3115 + 1015,
+			matches:    true,
+		},
+		{
+			name:       "explicit zero hem allowance",
+			rawData:    `{"version": 1, "measurements": [` + hallCurtain(`, "opening": "Single Open", "hemAllowance": 0`) + `]}`,
+			clothCost:  1939,
+			grandTotal: 2824 + 1015,
+			matches:    true,
+		},
+		{
+			// A version 1 payload without one is priced with the current 15
+			name:       "current payload without a hem allowance",
+			rawData:    `{"version": 1, "measurements": [` + hallCurtain(`, "opening": "Single Open", "clothCost": 2216`) + `]}`,
+			clothCost:  2285,
+			grandTotal: 3187 + 1015,
+			matches:    false,
+		},
+		{
+			// 4 ft x 5 ft of net at 25 adds 500
+			name: "curtain and mosquito net",
+			rawData: `{"version": 1, "measurements": [` + hallCurtain(`, "opening": "Single Open", "hemAllowance": 15`) + `,
+				{"interiorType": "mosquito-nets", "roomLabel": "Bedroom", "width": 48, "height": 60, "materialType": "Fibre net", "materialRatePerSqft": 25}]}`,
+			clothCost:  2285,
+			grandTotal: 3187 + 1015 + 500,
+			matches:    true,
+		},
+	}
+	for _, tt := range tests {
+		q, err := QuoteProject(tt.rawData)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		curtain := q.Curtains.Rooms[0].Measurements[0]
+		if curtain.ClothCost != tt.clothCost {
+			t.Errorf("%s: clothCost = %v, want %v", tt.name, curtain.ClothCost, tt.clothCost)
+		}
+		if q.GrandTotal != tt.grandTotal {
+			t.Errorf("%s: grandTotal = %v, want %v", tt.name, q.GrandTotal, tt.grandTotal)
+		}
+		if q.Curtains.Matches != tt.matches {
+			t.Errorf("%s: matches = %v, mismatches %+v", tt.name, q.Curtains.Matches, q.Curtains.Mismatches)
+		}
+	}
+}
+
+func TestCompareSubmitted(t *testing.T) {
+	tests := []struct {
+		name      string
+		submitted string
+		fields    []string
+	}{
+		{"nothing submitted", ``, nil},
+		{"every value matches", `, "clothCost": 2285, "stitchingCost": 750, "liningCost": 0, "totalCurtainCost": 3035, "clampCost": 200, "doomCost": 60`, nil},
+		{"within the tolerance", `, "clothCost": 2284, "totalCurtainCost": 3036`, nil},
+		{"numeric strings", `, "clothCost": "2285", "doomCost": "60"`, nil},
+		{"beyond the tolerance", `, "clothCost": 2283, "stitchingCost": 750, "doomCost": 90`, []string{"clothCost", "doomCost"}},
+	}
+	for _, tt := range tests {
+		p, err := projectdata.ParseString(`{"version": 1, "measurements": [` + hallCurtain(`, "opening": "Single Open", "hemAllowance": 15`+tt.submitted) + `]}`)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		curtains := p.Curtains()
+		inputs := []CurtainInput{curtainInput(curtains[0])}
+		mismatches := compareSubmitted(Quote(inputs), curtains)
+		var fields []string
+		for _, m := range mismatches {
+			fields = append(fields, m.Field)
+		}
+		if fmt.Sprint(fields) != fmt.Sprint(tt.fields) {
+			t.Errorf("%s: mismatched fields %v, want %v", tt.name, fields, tt.fields)
+		}
+	}
+}
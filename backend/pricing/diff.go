@@ -0,0 +1,130 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/projectdata"
+)
+
+// FieldChange is one measurement field that differs between two revisions
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ItemChange is a measurement added, removed or changed between two
+// revisions. Key is the measurement's id, or #<index> when the app sent none.
+type ItemChange struct {
+	Key        string              `json:"key"`
+	From       *models.Measurement `json:"from,omitempty"`
+	To         *models.Measurement `json:"to,omitempty"`
+	Fields     []FieldChange       `json:"fields,omitempty"`
+	TotalDelta float64             `json:"totalDelta"`
+}
+
+// Diff compares the measurements of two revisions of a project. Item totals
+// are the normalized row totals; FromTotal and ToTotal are the quotation
+// grand totals.
+type Diff struct {
+	Added      []ItemChange `json:"added"`
+	Removed    []ItemChange `json:"removed"`
+	Changed    []ItemChange `json:"changed"`
+	FromTotal  float64      `json:"fromTotal"`
+	ToTotal    float64      `json:"toTotal"`
+	TotalDelta float64      `json:"totalDelta"`
+}
+
+// diffIgnored are the row fields that identify a stored row rather than describe the item
+var diffIgnored = map[string]bool{"id": true, "projectId": true, "roomId": true, "position": true}
+
+// DiffRawData compares two rawData payloads measurement by measurement
+func DiffRawData(from, to string) (*Diff, error) {
+	before, err := keyedRows(from)
+	if err != nil {
+		return nil, revisionError("from", err)
+	}
+	after, err := keyedRows(to)
+	if err != nil {
+		return nil, revisionError("to", err)
+	}
+	d := &Diff{Added: []ItemChange{}, Removed: []ItemChange{}, Changed: []ItemChange{}}
+	d.FromTotal, d.ToTotal = before.total, after.total
+	d.TotalDelta = d.ToTotal - d.FromTotal
+
+	for _, key := range before.keys {
+		old := before.rows[key]
+		m, ok := after.rows[key]
+		if !ok {
+			d.Removed = append(d.Removed, ItemChange{Key: key, From: old, TotalDelta: -old.Total})
+			continue
+		}
+		if fields := changedFields(old, m); len(fields) > 0 {
+			d.Changed = append(d.Changed, ItemChange{Key: key, From: old, To: m, Fields: fields, TotalDelta: m.Total - old.Total})
+		}
+	}
+	for _, key := range after.keys {
+		if m := after.rows[key]; before.rows[key] == nil {
+			d.Added = append(d.Added, ItemChange{Key: key, To: m, TotalDelta: m.Total})
+		}
+	}
+	return d, nil
+}
+
+// revisionError tells which of the two payloads is invalid
+func revisionError(side string, err error) error {
+	var fields projectdata.ValidationErrors
+	if errors.As(err, &fields) {
+		return fields.Prefix(side)
+	}
+	return fmt.Errorf("%s: %w", side, err)
+}
+
+// revisionRows are the normalized measurement rows of one revision by key
+type revisionRows struct {
+	keys  []string
+	rows  map[string]*models.Measurement
+	total float64
+}
+
+func keyedRows(rawData string) (*revisionRows, error) {
+	r := &revisionRows{rows: make(map[string]*models.Measurement)}
+	if rawData == "" {
+		return r, nil
+	}
+	p, err := projectdata.ParseString(rawData)
+	if err != nil {
+		return nil, err
+	}
+	r.total = quoteProject(p).GrandTotal
+	_, measurements := Rows(p)
+	for i := range measurements {
+		key := p.Measurements[i].Common().ID
+		if key == "" || r.rows[key] != nil {
+			key = fmt.Sprintf("#%d", i)
+		}
+		r.keys = append(r.keys, key)
+		r.rows[key] = &measurements[i]
+	}
+	return r, nil
+}
+
+// changedFields lists the descriptive fields that differ between two rows
+func changedFields(from, to *models.Measurement) []FieldChange {
+	var changes []FieldChange
+	a, b := reflect.ValueOf(from).Elem(), reflect.ValueOf(to).Elem()
+	for i := 0; i < a.NumField(); i++ {
+		name, _, _ := strings.Cut(a.Type().Field(i).Tag.Get("json"), ",")
+		if diffIgnored[name] {
+			continue
+		}
+		if x, y := a.Field(i).Interface(), b.Field(i).Interface(); x != y {
+			changes = append(changes, FieldChange{Field: name, From: x, To: y})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,162 @@
+package pricing
+
+import (
+	"math"
+
+	"github.com/Vanaraj10/interior-backend/projectdata"
+)
+
+const (
+	// SqInchesPerSqft converts width × height in inches to square feet
+	SqInchesPerSqft = 144.0
+	// SqftPerWallpaperRoll is the wall area covered by one roll of wallpaper
+	SqftPerWallpaperRoll = 50.0
+	// WallpaperRoundUpFraction is the part of a roll from which the roll count rounds up
+	WallpaperRoundUpFraction = 0.3
+	// RomanBlindHemAllowance is the extra cloth (inches) added to a Roman blind's height
+	RomanBlindHemAllowance = 12.0
+)
+
+// Interior types other than curtains, as sent in a measurement's interiorType
+const (
+	MosquitoNets = projectdata.MosquitoNets
+	Wallpapers   = projectdata.Wallpapers
+	Blinds       = projectdata.Blinds
+	Flooring     = projectdata.Flooring
+)
+
+// ItemBreakdown is the recomputed pricing of a mosquito net, wallpaper, blind or
+// flooring measurement. Quantity is the wallpaper roll count or the Roman blind
+// part count; ExtraCost is the laying, implementation or Roman blind cloth and
+// stitching cost.
+type ItemBreakdown struct {
+	Index        int     `json:"index"`
+	InteriorType string  `json:"interiorType"`
+	RoomID       string  `json:"roomId"`
+	RoomName     string  `json:"roomName"`
+	RoomLabel    string  `json:"roomLabel"`
+	Kind         string  `json:"kind,omitempty"`
+	Width        float64 `json:"width"`
+	Height       float64 `json:"height"`
+	Sqft         float64 `json:"sqft"`
+	Quantity     float64 `json:"quantity,omitempty"`
+	MaterialCost float64 `json:"materialCost"`
+	ExtraCost    float64 `json:"extraCost"`
+	Total        float64 `json:"total"`
+}
+
+// ProjectQuotation prices every interior type of a project
+type ProjectQuotation struct {
+	Curtains   *Quotation         `json:"curtains"`
+	Items      []ItemBreakdown    `json:"items"`
+	Subtotals  map[string]float64 `json:"subtotals"`
+	GrandTotal float64            `json:"grandTotal"`
+}
+
+// MosquitoNetSqft returns the net area, rounding each side to 0.1 ft first as the app does
+func MosquitoNetSqft(width, height float64) float64 {
+	widthFeet := math.Round(width/12*10) / 10
+	heightFeet := math.Round(height/12*10) / 10
+	return widthFeet * heightFeet
+}
+
+// WallpaperRolls returns the rolls needed for a wall; a part roll of at least
+// WallpaperRoundUpFraction counts as a whole roll, and at least one roll is used
+func WallpaperRolls(width, height float64) float64 {
+	rolls := width * height / SqInchesPerSqft / SqftPerWallpaperRoll
+	if rolls-math.Floor(rolls) >= WallpaperRoundUpFraction {
+		return math.Ceil(rolls)
+	}
+	return math.Max(1, math.Floor(rolls))
+}
+
+// RomanBlindParts returns the number of panels for a Roman blind of the given width
+func RomanBlindParts(width float64, panelWidth string) float64 {
+	step := 45.0
+	if panelWidth == `56"` {
+		step = 50
+	}
+	if width <= step {
+		return 1
+	}
+	return math.Ceil(width / step)
+}
+
+// PriceItem prices a non-curtain measurement; ok is false for curtains
+func PriceItem(m projectdata.Measurement) (b ItemBreakdown, ok bool) {
+	common := m.Common()
+	b = ItemBreakdown{
+		InteriorType: common.InteriorType,
+		RoomID:       common.RoomID,
+		RoomName:     common.RoomName,
+		RoomLabel:    common.RoomLabel,
+		Width:        common.Width.Float(),
+		Height:       common.Height.Float(),
+	}
+	area := b.Width * b.Height / SqInchesPerSqft
+
+	switch m := m.(type) {
+	case *projectdata.MosquitoNet:
+		b.Kind = m.MaterialType
+		b.Sqft = MosquitoNetSqft(b.Width, b.Height)
+		b.MaterialCost = math.Ceil(b.Sqft * m.MaterialRatePerSqft.Float())
+	case *projectdata.Wallpaper:
+		b.Sqft = area
+		b.Quantity = WallpaperRolls(b.Width, b.Height)
+		b.MaterialCost = math.Ceil(b.Quantity * m.CostPerRoll.Float())
+		b.ExtraCost = math.Ceil(b.Quantity * m.ImplementationCostPerRoll.Float())
+	case *projectdata.Blind:
+		b.Kind = m.BlindType
+		b.Sqft = area
+		b.MaterialCost = math.Ceil(area * m.CostPerSqft.Float())
+		if b.Kind == projectdata.RomanBlinds {
+			b.Quantity = RomanBlindParts(b.Width, m.PanelWidth)
+			cloth := (b.Height + RomanBlindHemAllowance) / MetreDivisor * b.Quantity
+			b.ExtraCost = math.Ceil(cloth*m.ClothCostPerSqft.Float()) + math.Ceil(b.Quantity*m.StitchingCostPerPart.Float())
+		}
+	case *projectdata.FlooringItem:
+		b.Sqft = area
+		b.MaterialCost = math.Ceil(area * m.CostPerSqft.Float())
+		b.ExtraCost = math.Ceil(area * m.LayingPerSqft.Float())
+	default:
+		return ItemBreakdown{}, false
+	}
+	b.Total = b.MaterialCost + b.ExtraCost
+	return b, true
+}
+
+// QuoteProject parses a project's rawData JSON and prices all of its measurements
+func QuoteProject(rawData string) (*ProjectQuotation, error) {
+	p, err := projectdata.ParseString(rawData)
+	if err != nil {
+		return nil, err
+	}
+	return quoteProject(p), nil
+}
+
+func quoteProject(p *projectdata.Project) *ProjectQuotation {
+	q := &ProjectQuotation{
+		Curtains:  quoteCurtains(p),
+		Items:     []ItemBreakdown{},
+		Subtotals: map[string]float64{},
+	}
+	if len(q.Curtains.Rooms) > 0 {
+		q.Subtotals["curtains"] = q.Curtains.GrandTotal
+	}
+	for i, m := range p.Measurements {
+		b, ok := PriceItem(m)
+		if !ok {
+			continue
+		}
+		b.Index = i
+		if b.RoomName == "" {
+			b.RoomName = p.RoomName(b.RoomID)
+		}
+		q.Items = append(q.Items, b)
+		q.Subtotals[b.InteriorType] += b.Total
+	}
+	for _, total := range q.Subtotals {
+		q.GrandTotal += total
+	}
+	return q
+}
@@ -0,0 +1,104 @@
+package pricing
+
+import (
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/projectdata"
+)
+
+// RowsFromRawData parses a project's rawData and normalizes it with Rows.
+// A project without rawData has no rows.
+func RowsFromRawData(rawData string) ([]models.Room, []models.Measurement, error) {
+	if rawData == "" {
+		return nil, nil, nil
+	}
+	p, err := projectdata.ParseString(rawData)
+	if err != nil {
+		return nil, nil, err
+	}
+	rooms, measurements := Rows(p)
+	return rooms, measurements, nil
+}
+
+// Rows normalizes a project's rooms and measurements into rows for the rooms
+// and measurements tables, priced with the server's formulas. Measurement rows
+// refer to their room by RoomKey; RoomID is set when they are stored.
+func Rows(p *projectdata.Project) ([]models.Room, []models.Measurement) {
+	rooms := []models.Room{}
+	seen := make(map[string]int)
+	addRoom := func(key, name, roomType string) {
+		if key == "" {
+			return
+		}
+		if i, ok := seen[key]; ok {
+			// Rooms sent without a type take it from their measurements
+			if rooms[i].Type == "" {
+				rooms[i].Type = roomType
+			}
+			return
+		}
+		seen[key] = len(rooms)
+		rooms = append(rooms, models.Room{RoomKey: key, Name: name, Type: roomType})
+	}
+	for _, r := range p.Rooms {
+		addRoom(r.ID, r.Name, r.Type)
+	}
+
+	measurements := make([]models.Measurement, 0, len(p.Measurements))
+	for i, m := range p.Measurements {
+		common := m.Common()
+		roomType := common.InteriorType
+		if roomType == projectdata.Curtains {
+			roomType = "curtain"
+		}
+		name := common.RoomName
+		if name == "" {
+			name = p.RoomName(common.RoomID)
+		}
+		addRoom(common.RoomID, name, roomType)
+
+		row := models.Measurement{
+			Position:     i,
+			InteriorType: common.InteriorType,
+			RoomKey:      common.RoomID,
+			RoomLabel:    common.RoomLabel,
+			Width:        common.Width.Float(),
+			Height:       common.Height.Float(),
+		}
+		if c, ok := m.(*projectdata.Curtain); ok {
+			b := PriceMeasurement(curtainInput(c))
+			row.Model = b.StitchingModel
+			row.Parts = b.Parts
+			row.Meters = b.Meters
+			row.MaterialRate = c.ClothRatePerMeter.Float()
+			row.LabourRate = c.StitchingCostPerPart.Float()
+			row.MaterialCost = b.ClothCost + b.LiningCost
+			row.LabourCost = b.StitchingCost
+			row.HardwareCost = b.WallBracketCost
+		} else if b, ok := PriceItem(m); ok {
+			row.Model = b.Kind
+			row.Sqft = b.Sqft
+			row.MaterialCost = b.MaterialCost
+			row.LabourCost = b.ExtraCost
+			switch m := m.(type) {
+			case *projectdata.MosquitoNet:
+				row.MaterialRate = m.MaterialRatePerSqft.Float()
+			case *projectdata.Wallpaper:
+				row.Rolls = b.Quantity
+				row.MaterialRate = m.CostPerRoll.Float()
+				row.LabourRate = m.ImplementationCostPerRoll.Float()
+			case *projectdata.Blind:
+				row.Parts = b.Quantity
+				row.MaterialRate = m.CostPerSqft.Float()
+				if b.Quantity > 0 {
+					row.LabourRate = m.StitchingCostPerPart.Float()
+				}
+			case *projectdata.FlooringItem:
+				row.MaterialRate = m.CostPerSqft.Float()
+				row.LabourRate = m.LayingPerSqft.Float()
+			}
+		}
+		row.Total = row.MaterialCost + row.LabourCost + row.HardwareCost
+		measurements = append(measurements, row)
+	}
+	return rooms, measurements
+}
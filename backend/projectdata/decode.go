@@ -0,0 +1,98 @@
+package projectdata
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var numberType = reflect.TypeOf(Number(0))
+
+// decodeObject strictly decodes a JSON object into the struct v points to and
+// returns the fields that were sent with a value, or nil if raw is not an object.
+// Unlike json.Decoder.DisallowUnknownFields it reports every unknown field
+// and every mistyped value with its full path instead of stopping at the first.
+func decodeObject(raw json.RawMessage, v interface{}, path string, errs *ValidationErrors) map[string]bool {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil || obj == nil {
+		errs.add(path, RuleType, "must be an object")
+		return nil
+	}
+	fields := jsonFields(reflect.ValueOf(v).Elem())
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	present := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		f, ok := fields[key]
+		if !ok {
+			errs.add(field(path, key), RuleUnknown, "unknown field")
+			continue
+		}
+		if err := json.Unmarshal(obj[key], f.Addr().Interface()); err != nil {
+			errs.add(field(path, key), RuleType, "%s", typeMessage(f.Type()))
+			continue
+		}
+		value := string(obj[key])
+		present[key] = value != "null" && value != `""`
+	}
+	return present
+}
+
+// decodeArray decodes a JSON array into its raw elements; null is an empty array
+func decodeArray(raw json.RawMessage, path string, errs *ValidationErrors) []json.RawMessage {
+	var items []json.RawMessage
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		errs.add(path, RuleType, "must be an array")
+		return nil
+	}
+	return items
+}
+
+// jsonFields maps the JSON names of a struct's fields, including those of
+// embedded structs, to the addressable field values
+func jsonFields(v reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			for name, f := range jsonFields(v.Field(i)) {
+				fields[name] = f
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = v.Field(i)
+	}
+	return fields
+}
+
+func typeMessage(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == numberType:
+		return "must be a number"
+	case t.Kind() == reflect.String:
+		return "must be a string"
+	case t.Kind() == reflect.Bool:
+		return "must be true or false"
+	case t.Kind() == reflect.Int:
+		return "must be a whole number"
+	}
+	return "has an invalid value"
+}
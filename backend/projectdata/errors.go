@@ -0,0 +1,67 @@
+package projectdata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rules reported with a FieldError
+const (
+	RuleRequired = "required"
+	RuleType     = "type"
+	RuleUnknown  = "unknown"
+	RuleOptions  = "options"
+	RuleMin      = "min"
+	RuleMax      = "max"
+)
+
+// FieldError is a problem with one field, e.g. measurements[2].width
+type FieldError struct {
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors lists every invalid field of a payload
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Path + ": " + fe.Message
+	}
+	return "invalid project data: " + strings.Join(parts, "; ")
+}
+
+// Prefix returns the errors with path prepended to each field path
+func (e ValidationErrors) Prefix(path string) ValidationErrors {
+	prefixed := make(ValidationErrors, len(e))
+	for i, fe := range e {
+		fe.Path = field(path, fe.Path)
+		prefixed[i] = fe
+	}
+	return prefixed
+}
+
+// add records a problem; only the first problem with a field is kept
+func (e *ValidationErrors) add(path, rule, format string, args ...interface{}) {
+	for _, fe := range *e {
+		if fe.Path == path {
+			return
+		}
+	}
+	*e = append(*e, FieldError{Path: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// field joins an object path and a key
+func field(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// index joins an array path and an index
+func index(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
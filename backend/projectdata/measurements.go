@@ -0,0 +1,152 @@
+package projectdata
+
+// Interior types, as sent in a measurement's interiorType
+const (
+	Curtains     = "curtains"
+	MosquitoNets = "mosquito-nets"
+	Wallpapers   = "wallpapers"
+	Blinds       = "blinds"
+	Flooring     = "flooring"
+)
+
+// InteriorTypes lists the supported interior types in the order the app shows them
+var InteriorTypes = []string{Curtains, MosquitoNets, Wallpapers, Blinds, Flooring}
+
+// Measurement is one measured item of any interior type
+type Measurement interface {
+	// Common returns the fields shared by every interior type
+	Common() *Base
+}
+
+// newMeasurement returns an empty measurement of the given interior type
+func newMeasurement(interiorType string) Measurement {
+	switch interiorType {
+	case Curtains:
+		return &Curtain{}
+	case MosquitoNets:
+		return &MosquitoNet{}
+	case Wallpapers:
+		return &Wallpaper{}
+	case Blinds:
+		return &Blind{}
+	case Flooring:
+		return &FlooringItem{}
+	}
+	return nil
+}
+
+// Base holds the fields shared by every measurement. Width and height are in inches.
+type Base struct {
+	ID           string `json:"id,omitempty"`
+	InteriorType string `json:"interiorType"`
+	RoomID       string `json:"roomId,omitempty"`
+	RoomName     string `json:"roomName,omitempty"`
+	RoomLabel    string `json:"roomLabel"`
+	Width        Number `json:"width"`
+	Height       Number `json:"height"`
+}
+
+// Common returns the shared fields
+func (b *Base) Common() *Base {
+	return b
+}
+
+// Curtain is a curtain measurement. The pointer fields are the app's own
+// calculations; they are optional and, apart from the hem allowance the
+// server prices with, only used to cross-check the server's.
+type Curtain struct {
+	Base
+	StitchingModel       string `json:"stitchingModel"`
+	Opening              string `json:"opening,omitempty"`
+	BracketModel         string `json:"curtainBracketModels,omitempty"`
+	ClothRatePerMeter    Number `json:"clothRatePerMeter"`
+	StitchingCostPerPart Number `json:"stitchingCostPerPart"`
+	RodRatePerLength     Number `json:"rodRatePerLength"`
+	ClampRequired        Number `json:"clampRequired"`
+	ClampRatePerPiece    Number `json:"clampRatePerPiece"`
+	DoomRequired         Number `json:"doomRequired"`
+	DoomRatePerPiece     Number `json:"doomRatePerPiece"`
+	HasLining            bool   `json:"hasLining"`
+	LiningModel          string `json:"liningModel,omitempty"`
+	LiningRatePerMeter   Number `json:"liningRatePerMeter"`
+
+	HemAllowance         *Number `json:"hemAllowance,omitempty"`
+	Parts                *Number `json:"parts,omitempty"`
+	MainMetre            *Number `json:"mainMetre,omitempty"`
+	ClothCost            *Number `json:"clothCost,omitempty"`
+	StitchingCost        *Number `json:"stitchingCost,omitempty"`
+	LiningMetre          *Number `json:"liningMetre,omitempty"`
+	LiningCost           *Number `json:"liningCost,omitempty"`
+	TotalCurtainCost     *Number `json:"totalCurtainCost,omitempty"`
+	RodFeet              *Number `json:"rodFeet,omitempty"`
+	ClampCost            *Number `json:"clampCost,omitempty"`
+	DoomCost             *Number `json:"doomCost,omitempty"`
+	TotalWallBracketCost *Number `json:"totalWallBracketCost,omitempty"`
+	TotalRodsRequired    *Number `json:"totalRodsRequired,omitempty"`
+	TotalRodCost         *Number `json:"totalRodCost,omitempty"`
+	ClothCostWithGST     *Number `json:"clothCostWithGST,omitempty"`
+	RodCostWithGST       *Number `json:"rodCostWithGST,omitempty"`
+	GrandTotal           *Number `json:"grandTotal,omitempty"`
+	TotalCost            *Number `json:"totalCost,omitempty"`
+}
+
+// MosquitoNet is a mosquito net measurement
+type MosquitoNet struct {
+	Base
+	MaterialType        string `json:"materialType"`
+	MaterialRatePerSqft Number `json:"materialRatePerSqft"`
+	CustomDescription   string `json:"customDescription,omitempty"`
+
+	WidthFeet    *Number `json:"widthFeet,omitempty"`
+	HeightFeet   *Number `json:"heightFeet,omitempty"`
+	TotalSqft    *Number `json:"totalSqft,omitempty"`
+	MaterialCost *Number `json:"materialCost,omitempty"`
+	TotalCost    *Number `json:"totalCost,omitempty"`
+}
+
+// Wallpaper is a wall measured for wallpaper
+type Wallpaper struct {
+	Base
+	CostPerRoll               Number `json:"costPerRoll"`
+	ImplementationCostPerRoll Number `json:"implementationCostPerRoll"`
+
+	SquareInches            *Number `json:"squareInches,omitempty"`
+	SquareFeet              *Number `json:"squareFeet,omitempty"`
+	Rolls                   *Number `json:"rolls,omitempty"`
+	TotalMaterialCost       *Number `json:"totalMaterialCost,omitempty"`
+	TotalImplementationCost *Number `json:"totalImplementationCost,omitempty"`
+	TotalCost               *Number `json:"totalCost,omitempty"`
+}
+
+// RomanBlinds is the blind type priced with extra cloth and stitching
+const RomanBlinds = "Roman Blinds"
+
+// Blind is a blind measurement; the cloth fields apply to Roman blinds only
+type Blind struct {
+	Base
+	BlindType            string `json:"blindType"`
+	CostPerSqft          Number `json:"costPerSqft"`
+	ClothCostPerSqft     Number `json:"clothCostPerSqft"`
+	PanelWidth           string `json:"panelWidth,omitempty"`
+	StitchingCostPerPart Number `json:"stitchingCostPerPart"`
+
+	TotalSqft     *Number `json:"totalSqft,omitempty"`
+	BlindsCost    *Number `json:"blindsCost,omitempty"`
+	Part          *Number `json:"part,omitempty"`
+	ClothRequired *Number `json:"clothRequired,omitempty"`
+	ClothCost     *Number `json:"clothCost,omitempty"`
+	StitchingCost *Number `json:"stitchingCost,omitempty"`
+	TotalCost     *Number `json:"totalCost,omitempty"`
+}
+
+// FlooringItem is a floor measured for flooring
+type FlooringItem struct {
+	Base
+	CostPerSqft   Number `json:"costPerSqft"`
+	LayingPerSqft Number `json:"layingPerSqft"`
+
+	TotalSqft    *Number `json:"totalSqft,omitempty"`
+	CostOfRoom   *Number `json:"costOfRoom,omitempty"`
+	LayingCharge *Number `json:"layingCharge,omitempty"`
+	TotalCost    *Number `json:"totalCost,omitempty"`
+}
@@ -0,0 +1,45 @@
+package projectdata
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var errNotNumber = errors.New("not a number")
+
+// Number is a measurement value. Older app versions stored form input as
+// strings, so a numeric string is accepted too; an empty string or null is 0.
+type Number float64
+
+// Float returns the value as a float64
+func (n Number) Float() float64 {
+	return float64(n)
+}
+
+// UnmarshalJSON accepts a JSON number, a numeric string, "" or null
+func (n *Number) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*n = 0
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			*n = 0
+			return nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return errNotNumber
+	}
+	*n = Number(f)
+	return nil
+}
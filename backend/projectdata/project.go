@@ -0,0 +1,164 @@
+// Package projectdata is the typed model of a project's rawData, the
+// measurement payload the mobile app uploads with each project.
+package projectdata
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Project is the rawData payload of a project. Measurements holds every
+// measurement, curtains included, in the order the app sent them.
+type Project struct {
+	Version      int           `json:"version"`
+	ProjectID    string        `json:"projectId,omitempty"`
+	CreatedDate  string        `json:"createdDate,omitempty"`
+	GrandTotal   Number        `json:"grandTotal"`
+	RodCost      Number        `json:"rodCost"`
+	Rooms        []Room        `json:"rooms"`
+	Measurements []Measurement `json:"measurements"`
+}
+
+// Room is a room the measurements refer to by id. Type is "curtain" for
+// curtain rooms, otherwise the interior type of its measurements.
+type Room struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// CurtainRoom is a room with its curtain measurements, as the app groups them
+type CurtainRoom struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Measurements []*Curtain `json:"measurements"`
+}
+
+// Parse decodes a rawData payload, upgrading it to CurrentVersion first, and
+// validates it. Problems with the content are returned as ValidationErrors.
+func Parse(rawData []byte) (*Project, error) {
+	upgraded, err := upgrade(rawData)
+	if err != nil {
+		return nil, err
+	}
+	p, errs := decode(upgraded)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return p, nil
+}
+
+// ParseString is Parse for rawData stored as a string
+func ParseString(rawData string) (*Project, error) {
+	return Parse([]byte(rawData))
+}
+
+// decode strictly decodes a payload that is already at CurrentVersion
+func decode(data []byte) (*Project, ValidationErrors) {
+	var errs ValidationErrors
+	var wire struct {
+		Version      int             `json:"version"`
+		ProjectID    string          `json:"projectId"`
+		CreatedDate  string          `json:"createdDate"`
+		GrandTotal   Number          `json:"grandTotal"`
+		RodCost      Number          `json:"rodCost"`
+		Rooms        json.RawMessage `json:"rooms"`
+		Measurements json.RawMessage `json:"measurements"`
+	}
+	if decodeObject(data, &wire, "", &errs) == nil {
+		return nil, errs
+	}
+	p := Project{
+		Version:      wire.Version,
+		ProjectID:    wire.ProjectID,
+		CreatedDate:  wire.CreatedDate,
+		GrandTotal:   wire.GrandTotal,
+		RodCost:      wire.RodCost,
+		Rooms:        []Room{},
+		Measurements: []Measurement{},
+	}
+
+	for i, raw := range decodeArray(wire.Rooms, "rooms", &errs) {
+		path := index("rooms", i)
+		var r Room
+		if decodeObject(raw, &r, path, &errs) != nil {
+			if r.ID == "" {
+				errs.add(field(path, "id"), RuleRequired, "is required")
+			}
+			p.Rooms = append(p.Rooms, r)
+		}
+	}
+
+	if len(wire.Measurements) == 0 {
+		errs.add("measurements", RuleRequired, "is required")
+	}
+	for i, raw := range decodeArray(wire.Measurements, "measurements", &errs) {
+		path := index("measurements", i)
+		var head struct {
+			InteriorType string `json:"interiorType"`
+		}
+		_ = json.Unmarshal(raw, &head)
+		schema := SchemaFor(head.InteriorType)
+		if schema == nil {
+			errs.add(field(path, "interiorType"), RuleOptions, "must be one of %s", strings.Join(InteriorTypes, ", "))
+			continue
+		}
+		m := newMeasurement(head.InteriorType)
+		if present := decodeObject(raw, m, path, &errs); present != nil {
+			schema.validate(m, present, path, &errs)
+			p.Measurements = append(p.Measurements, m)
+		}
+	}
+	return &p, errs
+}
+
+// UnmarshalJSON decodes and validates a payload like Parse
+func (p *Project) UnmarshalJSON(data []byte) error {
+	parsed, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	*p = *parsed
+	return nil
+}
+
+// RoomName returns the name of the room with the given id, or ""
+func (p *Project) RoomName(id string) string {
+	for _, r := range p.Rooms {
+		if r.ID == id {
+			return r.Name
+		}
+	}
+	return ""
+}
+
+// Curtains returns the curtain measurements in order
+func (p *Project) Curtains() []*Curtain {
+	var curtains []*Curtain
+	for _, m := range p.Measurements {
+		if c, ok := m.(*Curtain); ok {
+			curtains = append(curtains, c)
+		}
+	}
+	return curtains
+}
+
+// CurtainRooms groups the curtain measurements by room, in order of first appearance
+func (p *Project) CurtainRooms() []CurtainRoom {
+	var rooms []CurtainRoom
+	seen := make(map[string]int)
+	for _, c := range p.Curtains() {
+		i, ok := seen[c.RoomID]
+		if !ok {
+			name := c.RoomName
+			if name == "" {
+				name = p.RoomName(c.RoomID)
+			}
+			i = len(rooms)
+			seen[c.RoomID] = i
+			rooms = append(rooms, CurtainRoom{ID: c.RoomID, Name: name})
+		}
+		rooms[i].Measurements = append(rooms[i].Measurements, c)
+	}
+	return rooms
+}
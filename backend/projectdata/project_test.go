@@ -0,0 +1,145 @@
+package projectdata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// curtain is a valid curtain measurement with extra spliced in
+func curtain(extra string) string {
+	return `{"interiorType": "curtains", "roomId": "r1", "roomLabel": "Window", "width": 60, "height": 84,
+		"stitchingModel": "Pleated", "curtainBracketModels": "MS Rod", "opening": "Single Open",
+		"clothRatePerMeter": 300, "stitchingCostPerPart": 250, "rodRatePerLength": 600,
+		"clampRequired": 4, "clampRatePerPiece": 50, "doomRequired": 2, "doomRatePerPiece": 30` + extra + `}`
+}
+
+func TestUpgradeV0(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "curtain rooms are flattened into measurements",
+			in:   `{"projectId": 7, "curtainRooms": [{"id": 1, "name": "Hall", "measurements": [{"id": 3, "width": 60}]}]}`,
+			want: `{"measurements": [{"hemAllowance": 12, "id": "3", "interiorType": "curtains", "opening": "Single Open", "roomId": "1", "roomName": "Hall", "width": 60}],
+				"projectId": "7", "rooms": [{"id": "1", "name": "Hall", "type": "curtain"}]}`,
+		},
+		{
+			name: "legacy curtain fields are renamed",
+			in:   `{"measurements": [{"interiorType": "curtains", "curtainType": "Eyelet", "pieces": 3, "totalMeters": 7.6, "totalLiningCost": 0, "stitchingModel": ""}]}`,
+			want: `{"measurements": [{"hemAllowance": 12, "interiorType": "curtains", "liningCost": 0, "mainMetre": 7.6, "opening": "Single Open", "parts": 3, "stitchingModel": "Eyelet"}]}`,
+		},
+		{
+			name: "a current field wins over its legacy name",
+			in:   `{"measurements": [{"interiorType": "curtains", "curtainType": "Eyelet", "stitchingModel": "Pleated", "opening": "No open"}]}`,
+			want: `{"measurements": [{"hemAllowance": 12, "interiorType": "curtains", "opening": "No open", "stitchingModel": "Pleated"}]}`,
+		},
+		{
+			name: "a sent hem allowance is kept, even zero",
+			in:   `{"measurements": [{"interiorType": "curtains", "hemAllowance": 15}, {"interiorType": "curtains", "hemAllowance": 0}]}`,
+			want: `{"measurements": [{"hemAllowance": 15, "interiorType": "curtains", "opening": "Single Open"}, {"hemAllowance": 0, "interiorType": "curtains", "opening": "Single Open"}]}`,
+		},
+		{
+			name: "other interior types only get their room added",
+			in:   `{"rooms": [{"id": 1, "name": "Hall"}], "measurements": [{"interiorType": "blinds", "roomId": 1, "roomName": "Hall"}, {"interiorType": "flooring", "roomId": 2, "roomName": "Kitchen"}]}`,
+			want: `{"measurements": [{"interiorType": "blinds", "roomId": "1", "roomName": "Hall"}, {"interiorType": "flooring", "roomId": "2", "roomName": "Kitchen"}],
+				"rooms": [{"id": "1", "name": "Hall"}, {"id": "2", "name": "Kitchen", "type": "flooring"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		doc := decodeDoc(t, tt.in)
+		upgradeV0(doc)
+		got, _ := json.Marshal(doc)
+		want, _ := json.Marshal(decodeDoc(t, tt.want))
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, want)
+		}
+	}
+}
+
+func decodeDoc(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		t.Fatalf("%s: %v", s, err)
+	}
+	return doc
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawData string
+		// errs lists the expected errors as path:rule
+		errs []string
+	}{
+		{"valid", `{"version": 1, "measurements": [` + curtain(``) + `]}`, nil},
+		{"numeric strings", `{"version": 1, "measurements": [` + curtain(`, "hemAllowance": "15", "clothCost": ""`) + `]}`, nil},
+		{"unversioned", `{"measurements": [` + curtain(``) + `]}`, nil},
+		{"no measurements", `{"version": 1, "rooms": []}`, []string{"measurements:required"}},
+		{"unknown fields", `{"version": 1, "colour": "red", "measurements": [` + curtain(`, "fabric": "silk"`) + `]}`,
+			[]string{"colour:unknown", "measurements[0].fabric:unknown"}},
+		{"mistyped values", `{"version": 1, "measurements": [` + curtain(`, "hasLining": "yes", "hemAllowance": "a lot"`) + `]}`,
+			[]string{"measurements[0].hasLining:type", "measurements[0].hemAllowance:type"}},
+		{"unknown interior type", `{"version": 1, "measurements": [{"interiorType": "sofas"}]}`, []string{"measurements[0].interiorType:options"}},
+		{"room without an id", `{"version": 1, "rooms": [{"name": "Hall"}], "measurements": [` + curtain(``) + `]}`, []string{"rooms[0].id:required"}},
+		{"measurement that is not an object", `{"version": 1, "measurements": [3]}`, []string{"measurements[0].interiorType:options"}},
+		{"newer version", `{"version": 2, "measurements": []}`, []string{"version:max"}},
+	}
+	for _, tt := range tests {
+		_, err := ParseString(tt.rawData)
+		var got []string
+		var errs ValidationErrors
+		if errors.As(err, &errs) {
+			for _, fe := range errs {
+				got = append(got, fe.Path+":"+fe.Rule)
+			}
+		} else if err != nil {
+			t.Errorf("%s: %v is not a ValidationErrors", tt.name, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.errs) {
+			t.Errorf("%s: errors %v, want %v", tt.name, got, tt.errs)
+		}
+	}
+
+	if _, err := ParseString(`[]`); err == nil || errors.As(err, new(ValidationErrors)) {
+		t.Errorf("Parse of an array = %v, want a plain error", err)
+	}
+}
+
+func TestParseHemAllowance(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawData string
+		want    *float64
+	}{
+		{"legacy payload", `{"measurements": [` + curtain(``) + `]}`, ptr(12)},
+		{"current payload", `{"version": 1, "measurements": [` + curtain(``) + `]}`, nil},
+		{"sent by the app", `{"version": 1, "measurements": [` + curtain(`, "hemAllowance": 15`) + `]}`, ptr(15)},
+		{"zero", `{"version": 1, "measurements": [` + curtain(`, "hemAllowance": 0`) + `]}`, ptr(0)},
+	}
+	for _, tt := range tests {
+		p, err := ParseString(tt.rawData)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		got := p.Curtains()[0].HemAllowance
+		switch {
+		case got == nil && tt.want == nil:
+		case got == nil || tt.want == nil || got.Float() != *tt.want:
+			t.Errorf("%s: hemAllowance = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
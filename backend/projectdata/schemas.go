@@ -0,0 +1,217 @@
+package projectdata
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Field types, as in the app's form schemas
+const (
+	TextField     = "text"
+	NumberField   = "number"
+	PickerField   = "picker"
+	CheckboxField = "checkbox"
+)
+
+// Condition shows a field only when another field has the given value
+type Condition struct {
+	Field  string      `json:"field"`
+	Equals interface{} `json:"equals"`
+}
+
+// Field is one input of an interior type's measurement form
+type Field struct {
+	Name     string     `json:"name"`
+	Label    string     `json:"label"`
+	Type     string     `json:"type"`
+	Required bool       `json:"required"`
+	Options  []string   `json:"options,omitempty"`
+	Min      *float64   `json:"min,omitempty"`
+	ShowIf   *Condition `json:"showIf,omitempty"`
+}
+
+// Schema describes the measurement form of one interior type. Calculated
+// lists the fields the app computes from the form; they are optional.
+type Schema struct {
+	InteriorType string   `json:"interiorType"`
+	Label        string   `json:"label"`
+	Fields       []Field  `json:"fields"`
+	Calculated   []string `json:"calculated"`
+}
+
+func minimum(v float64) *float64 {
+	return &v
+}
+
+func text(name, label string, required bool) Field {
+	return Field{Name: name, Label: label, Type: TextField, Required: required}
+}
+
+// number fields never take negative values
+func number(name, label string, required bool) Field {
+	return Field{Name: name, Label: label, Type: NumberField, Required: required, Min: minimum(0)}
+}
+
+// size is a width or height in inches; a measured item is at least an inch
+func size(name, label string) Field {
+	return Field{Name: name, Label: label, Type: NumberField, Required: true, Min: minimum(1)}
+}
+
+func picker(name, label string, required bool, options ...string) Field {
+	return Field{Name: name, Label: label, Type: PickerField, Required: required, Options: options}
+}
+
+func when(f Field, field string, equals interface{}) Field {
+	f.ShowIf = &Condition{Field: field, Equals: equals}
+	return f
+}
+
+// Schemas mirrors INTERIOR_SCHEMAS in Mobile/app/components/interiorSchemas.js.
+// Keep the two in sync when a form changes.
+var Schemas = []Schema{
+	{
+		InteriorType: Curtains,
+		Label:        "Curtains",
+		Fields: []Field{
+			text("roomLabel", "Room", true),
+			size("width", "Width (inches)"),
+			size("height", "Height (inches)"),
+			picker("stitchingModel", "Stitching Model", true,
+				"Pleated", "Eyelet", "Plain Curtain", "Belt Model", "Ripple Curtain", "Button Model"),
+			number("clothRatePerMeter", "Cloth Rate/Metre (₹)", true),
+			number("stitchingCostPerPart", "Stitching Cost/Part (₹)", true),
+			picker("curtainBracketModels", "Curtain Bracket Models", true,
+				"MS Rod", "SS Rod(202 Grade)", "SS Rod(304 Grade)", "Brass Rod", "Decorative Rod", "Track Model"),
+			number("rodRatePerLength", "Rod Rate/Length (₹)", true),
+			number("clampRequired", "Clamp Required", true),
+			number("clampRatePerPiece", "Clamp Rate/Piece (₹)", true),
+			number("doomRequired", "Doom Required", true),
+			number("doomRatePerPiece", "Doom Rate/Piece (₹)", true),
+			picker("opening", "Opening", true, "Single Open", "No open"),
+			{Name: "hasLining", Label: "Add Lining", Type: CheckboxField},
+			when(picker("liningModel", "Lining Model", false,
+				"Blackout Lining", "Satin Lining", "Pure BlackOut Lining"), "hasLining", true),
+			when(number("liningRatePerMeter", "Lining Rate/Metre (₹)", false), "hasLining", true),
+		},
+	},
+	{
+		InteriorType: MosquitoNets,
+		Label:        "Mosquito Nets",
+		Fields: []Field{
+			text("roomLabel", "Room/Location Label", true),
+			size("width", "Width (inches)"),
+			size("height", "Height (inches)"),
+			picker("materialType", "Material Type", true,
+				"Fibre net", "S.S net", "Sleek net", "Magnatic net", "Pleated net", "Honey Comb"),
+			number("materialRatePerSqft", "Material Rate/Sqft (₹)", true),
+			text("customDescription", "Custom Description", false),
+		},
+	},
+	{
+		InteriorType: Wallpapers,
+		Label:        "Wallpapers",
+		Fields: []Field{
+			text("roomLabel", "Room/Location Label", true),
+			size("width", "Width (inches)"),
+			size("height", "Height (inches)"),
+			number("costPerRoll", "Cost per Roll (₹)", true),
+			number("implementationCostPerRoll", "Implementation Cost per Roll (₹)", true),
+		},
+	},
+	{
+		InteriorType: Blinds,
+		Label:        "Blinds",
+		Fields: []Field{
+			text("roomLabel", "Room/Location Label", true),
+			size("height", "Height (inches)"),
+			size("width", "Width (inches)"),
+			picker("blindType", "Blind Type", true,
+				RomanBlinds, "PVC Blinds", "Roller Blinds", "Zebra Blinds", "Vertical Blinds"),
+			number("costPerSqft", "Cost/Sqft (₹)", true),
+			when(number("clothCostPerSqft", "Cloth Cost/Sqft (₹)", false), "blindType", RomanBlinds),
+			when(picker("panelWidth", "Panel Width", false, `48"`, `56"`), "blindType", RomanBlinds),
+			when(number("stitchingCostPerPart", "Stitching Cost/Part (₹)", false), "blindType", RomanBlinds),
+		},
+	},
+	{
+		InteriorType: Flooring,
+		Label:        "Flooring",
+		Fields: []Field{
+			text("roomLabel", "Room", false),
+			number("height", "Height (inches)", false),
+			number("width", "Width (inches)", false),
+			number("costPerSqft", "Cost/Sqft", false),
+			number("layingPerSqft", "Laying/Sqft", false),
+		},
+	},
+}
+
+func init() {
+	for i := range Schemas {
+		Schemas[i].Calculated = calculatedFields(newMeasurement(Schemas[i].InteriorType))
+	}
+}
+
+// SchemaFor returns the schema of an interior type, or nil if it is unknown
+func SchemaFor(interiorType string) *Schema {
+	for i := range Schemas {
+		if Schemas[i].InteriorType == interiorType {
+			return &Schemas[i]
+		}
+	}
+	return nil
+}
+
+// calculatedFields lists the JSON names of a measurement's optional pointer fields
+func calculatedFields(m Measurement) []string {
+	var names []string
+	t := reflect.TypeOf(m).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Type.Kind() == reflect.Pointer {
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// validate applies the schema's rules to a decoded measurement. present holds
+// the fields that were sent with a value; a hidden field is not checked.
+func (s *Schema) validate(m Measurement, present map[string]bool, path string, errs *ValidationErrors) {
+	values := jsonFields(reflect.ValueOf(m).Elem())
+	for _, f := range s.Fields {
+		if f.ShowIf != nil && !reflect.DeepEqual(values[f.ShowIf.Field].Interface(), f.ShowIf.Equals) {
+			continue
+		}
+		p := field(path, f.Name)
+		v := values[f.Name]
+		switch f.Type {
+		case NumberField:
+			if f.Required && !present[f.Name] {
+				errs.add(p, RuleRequired, "is required")
+			} else if n := v.Float(); f.Min != nil && n < *f.Min {
+				errs.add(p, RuleMin, "must be at least %s", strconv.FormatFloat(*f.Min, 'f', -1, 64))
+			}
+		case TextField, PickerField:
+			value := v.String()
+			if strings.TrimSpace(value) == "" {
+				if f.Required {
+					errs.add(p, RuleRequired, "is required")
+				}
+			} else if f.Type == PickerField && !contains(f.Options, value) {
+				errs.add(p, RuleOptions, "must be one of %s", strings.Join(f.Options, ", "))
+			}
+		}
+	}
+}
+
+func contains(options []string, value string) bool {
+	for _, o := range options {
+		if o == value {
+			return true
+		}
+	}
+	return false
+}
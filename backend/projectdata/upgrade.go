@@ -0,0 +1,149 @@
+package projectdata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// CurrentVersion is the rawData layout the structs in this package describe.
+// Payloads without a version are version 0, as uploaded by the mobile app.
+const CurrentVersion = 1
+
+// upgrades[v] converts a decoded payload from version v to version v+1.
+// Add a step here, and bump CurrentVersion, whenever the layout changes.
+var upgrades = []func(doc map[string]interface{}){
+	upgradeV0,
+}
+
+// upgrade brings a payload to CurrentVersion. Payloads from a newer server
+// are rejected rather than guessed at.
+func upgrade(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil || doc == nil {
+		return nil, fmt.Errorf("invalid rawData: must be a JSON object")
+	}
+	version := 0
+	if v, ok := doc["version"]; ok {
+		n, _ := v.(json.Number)
+		i, err := n.Int64()
+		if err != nil || i < 0 {
+			return nil, ValidationErrors{{Path: "version", Rule: RuleType, Message: "must be a whole number"}}
+		}
+		version = int(i)
+	}
+	if version > CurrentVersion {
+		return nil, ValidationErrors{{Path: "version", Rule: RuleMax, Message: fmt.Sprintf("must be at most %d", CurrentVersion)}}
+	}
+	if version == CurrentVersion {
+		return data, nil
+	}
+	for ; version < CurrentVersion; version++ {
+		upgrades[version](doc)
+	}
+	doc["version"] = CurrentVersion
+	return json.Marshal(doc)
+}
+
+// legacyHemAllowance is the hem allowance (inches) the app priced curtains
+// with before it sent hemAllowance
+const legacyHemAllowance = 12
+
+// legacyCurtainFields maps the duplicate field names older app versions
+// sent with curtains to the names used now
+var legacyCurtainFields = map[string]string{
+	"curtainType":     "stitchingModel",
+	"pieces":          "parts",
+	"totalMeters":     "mainMetre",
+	"totalLiningCost": "liningCost",
+}
+
+// upgradeV0 converts an unversioned app upload: curtain rooms the app had not
+// flattened are moved into measurements, numeric ids become strings, legacy
+// curtain field names are renamed, a missing curtain opening and hem allowance
+// are filled in, and rooms only named by a measurement are added to rooms.
+func upgradeV0(doc map[string]interface{}) {
+	measurements, _ := doc["measurements"].([]interface{})
+	rooms, _ := doc["rooms"].([]interface{})
+	known := make(map[string]bool)
+	for _, r := range rooms {
+		if room, ok := r.(map[string]interface{}); ok {
+			stringify(room, "id")
+			id, _ := room["id"].(string)
+			known[id] = true
+		}
+	}
+
+	if curtainRooms, ok := doc["curtainRooms"].([]interface{}); ok {
+		for _, r := range curtainRooms {
+			room, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			stringify(room, "id")
+			list, _ := room["measurements"].([]interface{})
+			for _, m := range list {
+				if m, ok := m.(map[string]interface{}); ok {
+					m["interiorType"] = Curtains
+					m["roomId"] = room["id"]
+					m["roomName"] = room["name"]
+					measurements = append(measurements, m)
+				}
+			}
+		}
+		delete(doc, "curtainRooms")
+	}
+
+	for _, m := range measurements {
+		m, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		stringify(m, "id")
+		stringify(m, "roomId")
+		if m["interiorType"] == Curtains {
+			// The app's calculation treated a missing opening as a single opening
+			if v, ok := m["opening"]; !ok || v == "" {
+				m["opening"] = "Single Open"
+			}
+			if _, ok := m["hemAllowance"]; !ok {
+				m["hemAllowance"] = json.Number(strconv.Itoa(legacyHemAllowance))
+			}
+			for old, current := range legacyCurtainFields {
+				if v, ok := m[old]; ok {
+					if cur, ok := m[current]; !ok || cur == "" {
+						m[current] = v
+					}
+					delete(m, old)
+				}
+			}
+		}
+		id, _ := m["roomId"].(string)
+		name, _ := m["roomName"].(string)
+		if id != "" && name != "" && !known[id] {
+			known[id] = true
+			roomType := "curtain"
+			if t, _ := m["interiorType"].(string); t != Curtains {
+				roomType = t
+			}
+			rooms = append(rooms, map[string]interface{}{"id": id, "name": name, "type": roomType})
+		}
+	}
+	if measurements != nil {
+		doc["measurements"] = measurements
+	}
+	if rooms != nil {
+		doc["rooms"] = rooms
+	}
+	stringify(doc, "projectId")
+}
+
+// stringify turns a numeric id into its string form
+func stringify(m map[string]interface{}, key string) {
+	if n, ok := m[key].(json.Number); ok {
+		m[key] = n.String()
+	}
+}
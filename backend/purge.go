@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/config"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+// purgeDeleted permanently removes projects and workers deleted more than
+// retention ago, and refresh tokens that expired as long ago. Projects go
+// first so that their workers can be purged too.
+func purgeDeleted(s *store.Store, retention time.Duration) (projects, workers, tokens int, err error) {
+	before := time.Now().Add(-retention)
+	if projects, err = s.Projects.PurgeProjects(before); err != nil {
+		return 0, 0, 0, fmt.Errorf("purging projects: %w", err)
+	}
+	if workers, err = s.Workers.PurgeWorkers(before); err != nil {
+		return projects, 0, 0, fmt.Errorf("purging workers: %w", err)
+	}
+	if tokens, err = s.Tokens.PurgeRefreshTokens(before); err != nil {
+		return projects, workers, 0, fmt.Errorf("purging refresh tokens: %w", err)
+	}
+	return projects, workers, tokens, nil
+}
+
+// runPurgeCommand handles `purge`, for running the purge from a scheduler
+func runPurgeCommand(s *store.Store, cfg config.PurgeConfig) {
+	projects, workers, tokens, err := purgeDeleted(s, cfg.Retention)
+	if err != nil {
+		log.Fatalf("purge: %v", err)
+	}
+	fmt.Printf("Purged %d project(s) and %d worker(s) deleted and %d refresh token(s) expired before %s\n", projects, workers, tokens, time.Now().Add(-cfg.Retention).Format(time.RFC3339))
+}
+
+// startPurgeJob runs the purge in the background every cfg.Interval, if set
+func startPurgeJob(s *store.Store, cfg config.PurgeConfig) {
+	if cfg.Interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			projects, workers, tokens, err := purgeDeleted(s, cfg.Retention)
+			if err != nil {
+				log.Printf("Purge failed: %v", err)
+				continue
+			}
+			if projects > 0 || workers > 0 || tokens > 0 {
+				log.Printf("Purged %d project(s), %d worker(s) and %d refresh token(s)", projects, workers, tokens)
+			}
+		}
+	}()
+	log.Printf("Purging deleted rows older than %s every %s", cfg.Retention, cfg.Interval)
+}
@@ -0,0 +1,160 @@
+// Package search turns projects into the terms of the search index and
+// search queries into the terms to look up. Matching and ranking are left to
+// the store: a query term matches every indexed term it is a prefix of, and
+// a project scores the weight of its best match for each query term, doubled
+// when the match is exact.
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/projectdata"
+)
+
+// Indexed fields
+const (
+	FieldClientName = "client_name"
+	FieldPhone      = "phone"
+	FieldAddress    = "address"
+	FieldRoom       = "room"
+	FieldMaterial   = "material"
+)
+
+// Weights of a match on each field
+var Weights = map[string]int{
+	FieldClientName: 10,
+	FieldPhone:      10,
+	FieldAddress:    4,
+	FieldRoom:       3,
+	FieldMaterial:   2,
+}
+
+const (
+	// MaxTermLength is the longest term stored; longer words are cut
+	MaxTermLength = 100
+	// MaxQueryTerms is how many words of a query are looked up
+	MaxQueryTerms = 5
+)
+
+// Terms returns the index terms of a project: the words of its client name,
+// address and the room names, labels and materials in its rawData, and its
+// normalized phone number. Each term is listed once, under its heaviest field.
+// A rawData that cannot be parsed adds nothing.
+func Terms(p *models.Project) []models.SearchTerm {
+	var terms []models.SearchTerm
+	seen := make(map[string]int)
+	add := func(field, term string) {
+		if term == "" {
+			return
+		}
+		if i, ok := seen[term]; ok {
+			if Weights[field] > terms[i].Weight {
+				terms[i].Field, terms[i].Weight = field, Weights[field]
+			}
+			return
+		}
+		seen[term] = len(terms)
+		terms = append(terms, models.SearchTerm{Field: field, Term: term, Weight: Weights[field]})
+	}
+	addWords := func(field, text string) {
+		for _, word := range Words(text) {
+			add(field, word)
+		}
+	}
+
+	addWords(FieldClientName, p.ClientName)
+	add(FieldPhone, NormalizePhone(p.Phone))
+	addWords(FieldAddress, p.Address)
+	if p.RawData == "" {
+		return terms
+	}
+	data, err := projectdata.ParseString(p.RawData)
+	if err != nil {
+		return terms
+	}
+	for _, r := range data.Rooms {
+		addWords(FieldRoom, r.Name)
+	}
+	for _, m := range data.Measurements {
+		common := m.Common()
+		addWords(FieldRoom, common.RoomName)
+		addWords(FieldRoom, common.RoomLabel)
+		switch m := m.(type) {
+		case *projectdata.Curtain:
+			addWords(FieldMaterial, m.StitchingModel)
+			addWords(FieldMaterial, m.LiningModel)
+		case *projectdata.MosquitoNet:
+			addWords(FieldMaterial, m.MaterialType)
+			addWords(FieldMaterial, m.CustomDescription)
+		case *projectdata.Blind:
+			addWords(FieldMaterial, m.BlindType)
+		}
+	}
+	return terms
+}
+
+// Query returns the terms to look up for a search. A query of only a phone
+// number, with or without +91, a leading zero and separators, is one term;
+// otherwise each word is a term.
+func Query(q string) []string {
+	if isPhone(q) {
+		if phone := NormalizePhone(q); phone != "" {
+			return []string{phone}
+		}
+	}
+	words := Words(q)
+	if len(words) > MaxQueryTerms {
+		words = words[:MaxQueryTerms]
+	}
+	return words
+}
+
+// Words splits text into lower-case words of letters and digits
+func Words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, f := range fields {
+		if r := []rune(f); len(r) > MaxTermLength {
+			fields[i] = string(r[:MaxTermLength])
+		}
+	}
+	return fields
+}
+
+// NormalizePhone reduces a phone number to its digits without the +91 or 0091
+// country code or a leading trunk zero, so every way of writing an Indian
+// number gives the same term. Part of a number normalizes to the start of
+// the whole one.
+func NormalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := strings.TrimLeft(digits.String(), "0")
+	international := strings.HasPrefix(strings.TrimSpace(phone), "+") || strings.HasPrefix(strings.TrimSpace(phone), "00")
+	if strings.HasPrefix(d, "91") && (international || len(d) == 12) {
+		d = d[2:]
+	}
+	return d
+}
+
+// isPhone reports whether a query looks like a phone number: digits and
+// separators only, with at least three digits
+func isPhone(q string) bool {
+	digits := 0
+	for _, r := range q {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' || r == '-' || r == '(' || r == ')' || r == '.' || unicode.IsSpace(r):
+		default:
+			return false
+		}
+	}
+	return digits >= 3
+}
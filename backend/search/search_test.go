@@ -0,0 +1,130 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Vanaraj10/interior-backend/models"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone, want string
+	}{
+		{"9876543210", "9876543210"},
+		{"98765 43210", "9876543210"},
+		{"+91 98765-43210", "9876543210"},
+		{"+919876543210", "9876543210"},
+		{"0091 98765 43210", "9876543210"},
+		{"919876543210", "9876543210"},
+		{"09876543210", "9876543210"},
+		{"(044) 2345 6789", "4423456789"},
+		// A number that only starts with 91 keeps it
+		{"9123456789", "9123456789"},
+		// Part of a number
+		{"98765", "98765"},
+		{"+91 98", "98"},
+		{"", ""},
+		{"no digits", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizePhone(tt.phone); got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"Anitha", []string{"anitha"}},
+		{"  Anitha   Anna Nagar ", []string{"anitha", "anna", "nagar"}},
+		{"+91 98765-43210", []string{"9876543210"}},
+		{"(98765) 432", []string{"98765432"}},
+		// Too few digits for a phone number
+		{"12", []string{"12"}},
+		// Digits with letters are words
+		{"flat 12b", []string{"flat", "12b"}},
+		{"a b c d e f g", []string{"a", "b", "c", "d", "e"}},
+		{"", nil},
+		{"--", nil},
+	}
+	for _, tt := range tests {
+		if got := Query(tt.q); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Query(%q) = %q, want %q", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	long := strings.Repeat("அ", MaxTermLength+5)
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Master Bedroom", []string{"master", "bedroom"}},
+		{"12/4, Gandhi St.", []string{"12", "4", "gandhi", "st"}},
+		{"Roman-blind", []string{"roman", "blind"}},
+		{long, []string{strings.Repeat("அ", MaxTermLength)}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.text); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		name    string
+		project models.Project
+		// want lists the expected terms as field:term
+		want []string
+	}{
+		{
+			name:    "client fields",
+			project: models.Project{ClientName: "Anitha Raman", Phone: "+91 98765 43210", Address: "4 Raman Street"},
+			want:    []string{"client_name:anitha", "client_name:raman", "phone:9876543210", "address:4", "address:street"},
+		},
+		{
+			name: "rooms and materials",
+			project: models.Project{ClientName: "Bala", RawData: `{"version": 1,
+				"rooms": [{"id": "r1", "name": "Hall"}],
+				"measurements": [
+					{"interiorType": "curtains", "roomId": "r1", "roomName": "Hall", "roomLabel": "Main Window", "width": 60, "height": 84,
+						"stitchingModel": "Pleated", "liningModel": "Blackout", "curtainBracketModels": "MS Rod", "opening": "Single Open",
+						"clothRatePerMeter": 300, "stitchingCostPerPart": 250, "rodRatePerLength": 600,
+						"clampRequired": 4, "clampRatePerPiece": 50, "doomRequired": 2, "doomRatePerPiece": 30},
+					{"interiorType": "mosquito-nets", "roomLabel": "Bedroom Window", "width": 48, "height": 60, "materialType": "Fibre net", "materialRatePerSqft": 25}]}`},
+			want: []string{"client_name:bala", "room:hall", "room:main", "room:window", "material:pleated", "material:blackout",
+				"room:bedroom", "material:fibre", "material:net"},
+		},
+		{
+			// The client's name outweighs a room of the same name
+			name:    "a term is kept under its heaviest field",
+			project: models.Project{ClientName: "Hall", RawData: `{"version": 1, "rooms": [{"id": "r1", "name": "Hall"}], "measurements": []}`},
+			want:    []string{"client_name:hall"},
+		},
+		{
+			name:    "unparseable rawData",
+			project: models.Project{ClientName: "Ravi", RawData: `{"measurements": "none"}`},
+			want:    []string{"client_name:ravi"},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, term := range Terms(&tt.project) {
+			if term.Weight != Weights[term.Field] {
+				t.Errorf("%s: %s weighs %d", tt.name, term.Term, term.Weight)
+			}
+			got = append(got, term.Field+":"+term.Term)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: terms %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) CreateOrganisation(o *models.Organisation, owner *models.Admin) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createOrganisation(o, owner)
+}
+
+func (s *Store) CreateFirstOrganisation(o *models.Organisation, owner *models.Admin) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.admins) > 0 {
+		return store.ErrConflict
+	}
+	return s.createOrganisation(o, owner)
+}
+
+// createOrganisation adds an organisation and its owner; callers must hold
+// the write lock
+func (s *Store) createOrganisation(o *models.Organisation, owner *models.Admin) error {
+	if s.usernameTaken(owner.Username) {
+		return store.ErrConflict
+	}
+	o.ID = s.newID("organisations")
+	o.CreatedAt = time.Now()
+	s.organisations[o.ID] = *o
+	owner.OrganisationID = o.ID
+	owner.ID = s.newID("admins")
+	owner.CreatedAt = o.CreatedAt
+	s.admins[owner.ID] = *owner
+	return nil
+}
+
+func (s *Store) usernameTaken(username string) bool {
+	for _, a := range s.admins {
+		if a.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) GetOrganisation(id int) (*models.Organisation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	o, ok := s.organisations[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &o, nil
+}
+
+func (s *Store) UpdateOrganisation(id int, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.organisations[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	o.Name = name
+	s.organisations[id] = o
+	return nil
+}
+
+func (s *Store) CreateAdmin(a *models.Admin) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.usernameTaken(a.Username) {
+		return store.ErrConflict
+	}
+	a.ID = s.newID("admins")
+	a.CreatedAt = time.Now()
+	s.admins[a.ID] = *a
+	return nil
+}
+
+func (s *Store) GetAdmin(id int) (*models.Admin, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, ok := s.admins[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (s *Store) GetAdminByUsername(username string) (*models.Admin, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, a := range s.admins {
+		if a.Username == username {
+			return &a, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *Store) UpdateAdminPassword(id int, passwordHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.admins[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	a.PasswordHash = passwordHash
+	s.admins[id] = a
+	return nil
+}
+
+func (s *Store) ListAdmins(orgID int) ([]models.Admin, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var admins []models.Admin
+	for _, a := range s.admins {
+		if a.OrganisationID == orgID {
+			admins = append(admins, a)
+		}
+	}
+	sort.Slice(admins, func(i, j int) bool { return admins[i].ID < admins[j].ID })
+	return admins, nil
+}
+
+func (s *Store) UpdateAdminRole(id, orgID int, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.admins[id]
+	if !ok || a.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	a.Role = role
+	s.admins[id] = a
+	return nil
+}
+
+func (s *Store) DeleteAdmin(id, orgID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.admins[id]
+	if !ok || a.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	delete(s.admins, id)
+	return nil
+}
+
+func (s *Store) CountAdmins() (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.admins), nil
+}
+
+func (s *Store) CreateInvite(inv *models.Invite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inv.ID = s.newID("invites")
+	inv.CreatedAt = time.Now()
+	s.invites[inv.CodeHash] = *inv
+	return nil
+}
+
+func (s *Store) RedeemInvite(codeHash string, o *models.Organisation, owner *models.Admin) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inv, ok := s.invites[codeHash]
+	now := time.Now()
+	if !ok || inv.UsedAt != nil || !inv.ExpiresAt.After(now) {
+		return store.ErrNotFound
+	}
+	if err := s.createOrganisation(o, owner); err != nil {
+		return err
+	}
+	orgID := o.ID
+	inv.UsedAt = &now
+	inv.OrganisationID = &orgID
+	s.invites[codeHash] = inv
+	return nil
+}
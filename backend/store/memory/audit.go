@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) RecordAudit(e *models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.ID = int64(s.newID("audit_log"))
+	e.CreatedAt = time.Now()
+	s.audit = append(s.audit, *e)
+	return nil
+}
+
+func (s *Store) ListAudit(orgID int, q store.AuditQuery) ([]models.AuditEntry, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := []models.AuditEntry{}
+	// Entries are appended in order, so walking backwards gives newest first
+	for i := len(s.audit) - 1; i >= 0; i-- {
+		e := s.audit[i]
+		if e.OrganisationID != orgID ||
+			(q.EntityType != "" && e.EntityType != q.EntityType) ||
+			(q.EntityID != nil && (e.EntityID == nil || *e.EntityID != *q.EntityID)) ||
+			(q.ActorRole != "" && e.ActorRole != q.ActorRole) ||
+			(q.ActorID != nil && e.ActorID != *q.ActorID) ||
+			(q.From != nil && e.CreatedAt.Before(*q.From)) ||
+			(q.To != nil && !e.CreatedAt.Before(*q.To)) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	total := len(entries)
+	if q.Offset >= total {
+		return []models.AuditEntry{}, total, nil
+	}
+	end := total
+	if q.Limit > 0 && q.Offset+q.Limit < total {
+		end = q.Offset + q.Limit
+	}
+	return entries[q.Offset:end], total, nil
+}
@@ -0,0 +1,307 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) CreateBrand(b *models.Brand) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b.ID = s.newID("brands")
+	s.brands[b.ID] = *b
+	return nil
+}
+
+func (s *Store) ListBrands(orgID int, filter store.BrandFilter) ([]models.Brand, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var brands []models.Brand
+	for _, b := range s.brands {
+		if b.OrganisationID != orgID {
+			continue
+		}
+		if filter.Active != nil && b.IsActive != *filter.Active {
+			continue
+		}
+		brands = append(brands, b)
+	}
+	sort.Slice(brands, func(i, j int) bool { return brands[i].Name < brands[j].Name })
+	return brands, nil
+}
+
+func (s *Store) GetBrand(id, orgID int) (*models.Brand, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.brands[id]
+	if !ok || b.OrganisationID != orgID {
+		return nil, store.ErrNotFound
+	}
+	return &b, nil
+}
+
+func (s *Store) UpdateBrand(id, orgID int, update store.BrandUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.brands[id]
+	if !ok || b.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	if update.Name != nil {
+		b.Name = *update.Name
+	}
+	if update.Description != nil {
+		b.Description = *update.Description
+	}
+	if update.LogoURL != nil {
+		b.LogoURL = *update.LogoURL
+	}
+	if update.IsActive != nil {
+		b.IsActive = *update.IsActive
+	}
+	b.UpdatedAt = time.Now()
+	s.brands[id] = b
+	return nil
+}
+
+func (s *Store) DeleteBrand(id, orgID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.brands[id]
+	if !ok || b.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	delete(s.brands, id)
+	return nil
+}
+
+func (s *Store) ActiveBrandExists(id, orgID int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.brands[id]
+	return ok && b.OrganisationID == orgID && b.IsActive, nil
+}
+
+func (s *Store) BrandHasFolders(id int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, f := range s.folders {
+		if f.BrandID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Store) CreateFolder(f *models.Folder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f.ID = s.newID("folders")
+	s.folders[f.ID] = *f
+	return nil
+}
+
+func (s *Store) ListFolders(orgID int, filter store.FolderFilter) ([]models.FolderWithBrand, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var folders []models.FolderWithBrand
+	for _, f := range s.folders {
+		if f.OrganisationID != orgID {
+			continue
+		}
+		if filter.BrandID != nil && f.BrandID != *filter.BrandID {
+			continue
+		}
+		if filter.Active != nil && f.IsActive != *filter.Active {
+			continue
+		}
+		b, ok := s.brands[f.BrandID]
+		if !ok {
+			continue
+		}
+		folders = append(folders, models.FolderWithBrand{Folder: f, BrandName: b.Name})
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].Name < folders[j].Name })
+	return folders, nil
+}
+
+func (s *Store) GetFolder(id, orgID int) (*models.FolderWithBrand, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, ok := s.folders[id]
+	if !ok || f.OrganisationID != orgID {
+		return nil, store.ErrNotFound
+	}
+	b, ok := s.brands[f.BrandID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &models.FolderWithBrand{Folder: f, BrandName: b.Name}, nil
+}
+
+func (s *Store) UpdateFolder(id, orgID int, update store.FolderUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.folders[id]
+	if !ok || f.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	if update.Name != nil {
+		f.Name = *update.Name
+	}
+	if update.Description != nil {
+		f.Description = *update.Description
+	}
+	if update.BrandID != nil {
+		f.BrandID = *update.BrandID
+	}
+	if update.IsActive != nil {
+		f.IsActive = *update.IsActive
+	}
+	f.UpdatedAt = time.Now()
+	s.folders[id] = f
+	return nil
+}
+
+func (s *Store) DeleteFolder(id, orgID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.folders[id]
+	if !ok || f.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	delete(s.folders, id)
+	return nil
+}
+
+func (s *Store) ActiveFolderExists(id, orgID int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, ok := s.folders[id]
+	return ok && f.OrganisationID == orgID && f.IsActive, nil
+}
+
+func (s *Store) FolderInBrand(folderID, brandID int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, ok := s.folders[folderID]
+	return ok && f.BrandID == brandID, nil
+}
+
+func (s *Store) FolderHasCloths(id int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, cl := range s.cloths {
+		if cl.FolderID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Store) CreateCloth(cl *models.Cloth) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cl.ID = s.newID("cloths")
+	s.cloths[cl.ID] = *cl
+	return nil
+}
+
+func (s *Store) ListCloths(orgID int, filter store.ClothFilter) ([]models.ClothWithDetails, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var cloths []models.ClothWithDetails
+	for _, cl := range s.cloths {
+		if cl.OrganisationID != orgID {
+			continue
+		}
+		if filter.BrandID != nil && cl.BrandID != *filter.BrandID {
+			continue
+		}
+		if filter.FolderID != nil && cl.FolderID != *filter.FolderID {
+			continue
+		}
+		if filter.Active != nil && cl.IsActive != *filter.Active {
+			continue
+		}
+		if details, ok := s.clothDetails(cl); ok {
+			cloths = append(cloths, details)
+		}
+	}
+	sort.Slice(cloths, func(i, j int) bool { return cloths[i].Name < cloths[j].Name })
+	return cloths, nil
+}
+
+func (s *Store) GetCloth(id, orgID int) (*models.ClothWithDetails, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cl, ok := s.cloths[id]
+	if !ok || cl.OrganisationID != orgID {
+		return nil, store.ErrNotFound
+	}
+	details, ok := s.clothDetails(cl)
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &details, nil
+}
+
+func (s *Store) UpdateCloth(id, orgID int, update store.ClothUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cl, ok := s.cloths[id]
+	if !ok || cl.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	if update.Name != nil {
+		cl.Name = *update.Name
+	}
+	if update.Rate != nil {
+		cl.Rate = *update.Rate
+	}
+	if update.Description != nil {
+		cl.Description = *update.Description
+	}
+	if update.ImageURL != nil {
+		cl.ImageURL = *update.ImageURL
+	}
+	if update.FolderID != nil {
+		cl.FolderID = *update.FolderID
+	}
+	if update.BrandID != nil {
+		cl.BrandID = *update.BrandID
+	}
+	if update.IsActive != nil {
+		cl.IsActive = *update.IsActive
+	}
+	cl.UpdatedAt = time.Now()
+	s.cloths[id] = cl
+	return nil
+}
+
+func (s *Store) DeleteCloth(id, orgID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cl, ok := s.cloths[id]
+	if !ok || cl.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	delete(s.cloths, id)
+	return nil
+}
+
+// clothDetails joins a cloth with its folder and brand names, like the SQL JOIN
+func (s *Store) clothDetails(cl models.Cloth) (models.ClothWithDetails, bool) {
+	f, ok := s.folders[cl.FolderID]
+	if !ok {
+		return models.ClothWithDetails{}, false
+	}
+	b, ok := s.brands[cl.BrandID]
+	if !ok {
+		return models.ClothWithDetails{}, false
+	}
+	return models.ClothWithDetails{Cloth: cl, FolderName: f.Name, BrandName: b.Name}, true
+}
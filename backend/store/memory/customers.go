@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) SaveCustomer(c *models.Customer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, existing := range s.customers {
+		if existing.OrganisationID != c.OrganisationID || existing.NormalizedPhone != c.NormalizedPhone {
+			continue
+		}
+		if existing.MergedIntoID != nil {
+			*c = s.customers[*existing.MergedIntoID]
+			return nil
+		}
+		existing.Name = c.Name
+		existing.Phone = c.Phone
+		existing.Address = c.Address
+		existing.UpdatedAt = now
+		s.customers[id] = existing
+		*c = existing
+		return nil
+	}
+	c.ID = s.newID("customers")
+	c.MergedIntoID = nil
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	s.customers[c.ID] = *c
+	return nil
+}
+
+func (s *Store) GetCustomer(id, orgID int) (*models.Customer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.customers[id]
+	if !ok || c.OrganisationID != orgID || c.MergedIntoID != nil {
+		return nil, store.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (s *Store) SetProjectCustomer(projectID int, customerID *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[projectID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	p.CustomerID = customerID
+	s.projects[projectID] = p
+	return nil
+}
+
+func (s *Store) MergeCustomers(orgID, intoID, fromID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	into, ok := s.customers[intoID]
+	from, ok2 := s.customers[fromID]
+	if !ok || !ok2 || intoID == fromID || into.OrganisationID != orgID || from.OrganisationID != orgID ||
+		into.MergedIntoID != nil || from.MergedIntoID != nil {
+		return store.ErrNotFound
+	}
+	for id, p := range s.projects {
+		if p.CustomerID != nil && *p.CustomerID == fromID {
+			p.CustomerID = &intoID
+			s.projects[id] = p
+		}
+	}
+	now := time.Now()
+	for id, c := range s.customers {
+		if id == fromID || (c.MergedIntoID != nil && *c.MergedIntoID == fromID) {
+			c.MergedIntoID = &intoID
+			c.UpdatedAt = now
+			s.customers[id] = c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) RecordFailedLogin(f *models.FailedLogin) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f.ID = int64(s.newID("failed_logins"))
+	f.CreatedAt = time.Now()
+	s.failedLogins = append(s.failedLogins, *f)
+	return nil
+}
+
+func (s *Store) ListFailedLogins(orgID int, q store.FailedLoginQuery) ([]models.FailedLogin, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	logins := []models.FailedLogin{}
+	for i := len(s.failedLogins) - 1; i >= 0; i-- {
+		f := s.failedLogins[i]
+		if f.OrganisationID == nil || *f.OrganisationID != orgID ||
+			(q.Role != "" && f.Role != q.Role) ||
+			(q.Username != "" && f.Username != q.Username) {
+			continue
+		}
+		logins = append(logins, f)
+	}
+	total := len(logins)
+	if q.Offset >= total {
+		return []models.FailedLogin{}, total, nil
+	}
+	end := total
+	if q.Limit > 0 && q.Offset+q.Limit < total {
+		end = q.Offset + q.Limit
+	}
+	return logins[q.Offset:end], total, nil
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"github.com/Vanaraj10/interior-backend/models"
+)
+
+func (s *Store) ReplaceProjectMeasurements(projectID int, rooms []models.Room, measurements []models.Measurement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	roomIDs := make(map[string]int)
+	storedRooms := make([]models.Room, len(rooms))
+	for i, r := range rooms {
+		r.ID = s.newID("rooms")
+		r.ProjectID = projectID
+		roomIDs[r.RoomKey] = r.ID
+		rooms[i], storedRooms[i] = r, r
+	}
+	storedMeasurements := make([]models.Measurement, len(measurements))
+	for i, m := range measurements {
+		m.ID = s.newID("measurements")
+		m.ProjectID = projectID
+		m.RoomID = nil
+		if id, ok := roomIDs[m.RoomKey]; ok {
+			m.RoomID = &id
+		}
+		measurements[i], storedMeasurements[i] = m, m
+	}
+	s.rooms[projectID] = storedRooms
+	s.measurements[projectID] = storedMeasurements
+	return nil
+}
+
+func (s *Store) ListProjectMeasurements(projectID int) ([]models.Room, []models.Measurement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rooms := append([]models.Room{}, s.rooms[projectID]...)
+	measurements := append([]models.Measurement{}, s.measurements[projectID]...)
+	return rooms, measurements, nil
+}
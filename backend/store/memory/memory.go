@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+// Store keeps every repository in process memory. Data is lost on restart,
+// which makes it suitable for local development, demos and tests.
+type Store struct {
+	mu            sync.RWMutex
+	nextID        map[string]int
+	organisations map[int]models.Organisation
+	admins        map[int]models.Admin
+	// invites is keyed by code hash
+	invites   map[string]models.Invite
+	workers   map[int]models.Worker
+	projects  map[int]models.Project
+	customers map[int]models.Customer
+	brands    map[int]models.Brand
+	folders   map[int]models.Folder
+	cloths    map[int]models.Cloth
+	// templates is keyed by organisation id
+	templates map[int]models.QuotationTemplate
+	// rooms and measurements are keyed by project id
+	rooms        map[int][]models.Room
+	measurements map[int][]models.Measurement
+	// transitions is keyed by project id
+	transitions map[int][]models.StatusTransition
+	// revisions is keyed by project id, oldest first
+	revisions map[int][]models.ProjectRevision
+	// searchTerms is keyed by project id
+	searchTerms map[int][]models.SearchTerm
+	audit       []models.AuditEntry
+	// refreshTokens is keyed by token hash
+	refreshTokens map[string]models.RefreshToken
+	failedLogins  []models.FailedLogin
+}
+
+// New creates an empty in-memory store
+func New() *store.Store {
+	s := &Store{
+		nextID:        make(map[string]int),
+		organisations: make(map[int]models.Organisation),
+		admins:        make(map[int]models.Admin),
+		invites:       make(map[string]models.Invite),
+		workers:       make(map[int]models.Worker),
+		projects:      make(map[int]models.Project),
+		customers:     make(map[int]models.Customer),
+		brands:        make(map[int]models.Brand),
+		folders:       make(map[int]models.Folder),
+		cloths:        make(map[int]models.Cloth),
+		templates:     make(map[int]models.QuotationTemplate),
+
+		rooms:        make(map[int][]models.Room),
+		measurements: make(map[int][]models.Measurement),
+		transitions:  make(map[int][]models.StatusTransition),
+		revisions:    make(map[int][]models.ProjectRevision),
+		searchTerms:  make(map[int][]models.SearchTerm),
+
+		refreshTokens: make(map[string]models.RefreshToken),
+	}
+	return &store.Store{Projects: s, Workers: s, Admins: s, Catalog: s, Templates: s, Measurements: s, Revisions: s, Audit: s, Tokens: s, Logins: s, Search: s, Customers: s}
+}
+
+// newID returns the next identity value for a table; callers must hold the write lock
+func (s *Store) newID(table string) int {
+	s.nextID[table]++
+	return s.nextID[table]
+}
@@ -0,0 +1,300 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/lifecycle"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) CreateProject(p *models.Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.createProject(p)
+	return nil
+}
+
+func (s *Store) createProject(p *models.Project) {
+	now := time.Now()
+	p.ID = s.newID("projects")
+	p.IsCompleted = false
+	p.Status = lifecycle.Measured
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	s.projects[p.ID] = *p
+}
+
+func (s *Store) UpdateProject(p *models.Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.overwriteProject(p)
+}
+
+func (s *Store) overwriteProject(p *models.Project) error {
+	existing, ok := s.projects[p.ID]
+	if !ok || existing.DeletedAt != nil || existing.WorkerID != p.WorkerID || existing.OrganisationID != p.OrganisationID {
+		return store.ErrNotFound
+	}
+	existing.ClientName = p.ClientName
+	existing.Phone = p.Phone
+	existing.Address = p.Address
+	existing.HTML = p.HTML
+	existing.RawData = p.RawData
+	existing.UpdatedAt = time.Now()
+	s.projects[p.ID] = existing
+	*p = existing
+	return nil
+}
+
+func (s *Store) SaveProject(p *models.Project, r *models.ProjectRevision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.ID > 0 {
+		if err := s.overwriteProject(p); err != nil {
+			return err
+		}
+	} else {
+		s.createProject(p)
+	}
+	r.ProjectID = p.ID
+	s.createRevision(r)
+	return nil
+}
+
+func (s *Store) GetProject(id, orgID int) (*models.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.projects[id]
+	if !ok || p.DeletedAt != nil || p.OrganisationID != orgID {
+		return nil, store.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (s *Store) ListProjectsByOrganisation(orgID int, q store.ProjectQuery) ([]models.Project, store.ProjectCounts, error) {
+	projects := s.filterProjects(func(p models.Project) bool {
+		return p.OrganisationID == orgID && (p.DeletedAt != nil) == q.Deleted &&
+			(q.Status == nil || p.Status == *q.Status) &&
+			(q.Completed == nil || p.IsCompleted == *q.Completed) &&
+			(q.WorkerID == nil || p.WorkerID == *q.WorkerID) &&
+			(q.CustomerID == nil || (p.CustomerID != nil && *p.CustomerID == *q.CustomerID)) &&
+			(q.InteriorType == "" || s.hasInteriorType(p.ID, q.InteriorType)) &&
+			strings.Contains(p.Phone, q.Phone) &&
+			(q.From == nil || !p.CreatedAt.Before(*q.From)) &&
+			(q.To == nil || p.CreatedAt.Before(*q.To))
+	})
+	var counts store.ProjectCounts
+	for _, p := range projects {
+		counts.Total++
+		if p.IsCompleted {
+			counts.Completed++
+		}
+	}
+	less := projectLess[q.Sort]
+	if less == nil {
+		less = projectLess[store.SortCreatedAt]
+	}
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := projects[i], projects[j]
+		if q.Desc {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		return !less(b, a) && a.ID < b.ID
+	})
+	if q.Offset >= len(projects) {
+		return nil, counts, nil
+	}
+	projects = projects[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(projects) {
+		projects = projects[:q.Limit]
+	}
+	for i := range projects {
+		projects[i].HTML = ""
+	}
+	return projects, counts, nil
+}
+
+// projectLess orders projects by each column ListProjectsByOrganisation sorts on
+var projectLess = map[store.ProjectSort]func(a, b models.Project) bool{
+	store.SortCreatedAt:  func(a, b models.Project) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	store.SortUpdatedAt:  func(a, b models.Project) bool { return a.UpdatedAt.Before(b.UpdatedAt) },
+	store.SortClientName: func(a, b models.Project) bool { return a.ClientName < b.ClientName },
+	store.SortStatus:     func(a, b models.Project) bool { return a.Status < b.Status },
+}
+
+// hasInteriorType reports whether a project has a measurement of the given
+// type. The caller holds the lock.
+func (s *Store) hasInteriorType(projectID int, interiorType string) bool {
+	for _, m := range s.measurements[projectID] {
+		if m.InteriorType == interiorType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) ListProjectsByID(ids []int, orgID int) ([]models.Project, error) {
+	projects := s.filterProjects(func(p models.Project) bool {
+		return p.OrganisationID == orgID && p.DeletedAt == nil && slices.Contains(ids, p.ID)
+	})
+	for i := range projects {
+		projects[i].HTML = ""
+	}
+	return projects, nil
+}
+
+func (s *Store) GetWorkerProject(id, workerID int) (*models.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.projects[id]
+	if !ok || p.DeletedAt != nil || p.WorkerID != workerID {
+		return nil, store.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (s *Store) ListProjectsByWorker(workerID int, q store.WorkerProjectQuery) ([]models.Project, int, error) {
+	projects := s.filterProjects(func(p models.Project) bool {
+		if q.Since == nil {
+			return p.WorkerID == workerID && p.DeletedAt == nil
+		}
+		return p.WorkerID == workerID && !p.UpdatedAt.Before(*q.Since)
+	})
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].UpdatedAt.Before(projects[j].UpdatedAt) })
+	total := len(projects)
+	if q.Offset >= total {
+		return nil, total, nil
+	}
+	end := total
+	if q.Limit > 0 && q.Offset+q.Limit < total {
+		end = q.Offset + q.Limit
+	}
+	return projects[q.Offset:end], total, nil
+}
+
+func (s *Store) ListProjectsAfter(afterID, limit int) ([]models.Project, error) {
+	projects := s.filterProjects(func(p models.Project) bool { return p.ID > afterID })
+	if len(projects) > limit {
+		projects = projects[:limit]
+	}
+	return projects, nil
+}
+
+func (s *Store) SetProjectStatus(t *models.StatusTransition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[t.ProjectID]
+	if !ok || p.DeletedAt != nil {
+		return store.ErrNotFound
+	}
+	if p.Status != t.FromStatus {
+		return store.ErrConflict
+	}
+	now := time.Now()
+	p.Status = t.ToStatus
+	if lifecycle.Completed(t.ToStatus) {
+		p.IsCompleted = true
+	}
+	p.UpdatedAt = now
+	s.projects[p.ID] = p
+
+	t.ID = s.newID("project_status_transitions")
+	t.CreatedAt = now
+	s.transitions[p.ID] = append(s.transitions[p.ID], *t)
+	return nil
+}
+
+func (s *Store) ListStatusTransitions(projectID int) ([]models.StatusTransition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.StatusTransition{}, s.transitions[projectID]...), nil
+}
+
+func (s *Store) DeleteProject(id, orgID int) error {
+	return s.updateProject(id, func(p models.Project) bool { return p.OrganisationID == orgID }, func(p *models.Project) {
+		now := time.Now()
+		p.DeletedAt = &now
+		p.UpdatedAt = now
+	})
+}
+
+func (s *Store) RestoreProject(id, orgID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[id]
+	if !ok || p.DeletedAt == nil || p.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	p.DeletedAt = nil
+	p.UpdatedAt = time.Now()
+	s.projects[id] = p
+	return nil
+}
+
+func (s *Store) ReassignProjects(fromWorkerID, toWorkerID, orgID int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for id, p := range s.projects {
+		if p.WorkerID == fromWorkerID && p.OrganisationID == orgID {
+			p.WorkerID = toWorkerID
+			p.UpdatedAt = time.Now()
+			s.projects[id] = p
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *Store) PurgeProjects(deletedBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for id, p := range s.projects {
+		if p.DeletedAt != nil && p.DeletedAt.Before(deletedBefore) {
+			delete(s.projects, id)
+			delete(s.rooms, id)
+			delete(s.measurements, id)
+			delete(s.transitions, id)
+			delete(s.revisions, id)
+			delete(s.searchTerms, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// filterProjects returns matching projects ordered by id
+func (s *Store) filterProjects(match func(models.Project) bool) []models.Project {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var projects []models.Project
+	for _, p := range s.projects {
+		if match(p) {
+			projects = append(projects, p)
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+	return projects
+}
+
+// updateProject applies change to a project if it exists, is not deleted and
+// passes the ownership check
+func (s *Store) updateProject(id int, owned func(models.Project) bool, change func(*models.Project)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[id]
+	if !ok || p.DeletedAt != nil || !owned(p) {
+		return store.ErrNotFound
+	}
+	change(&p)
+	s.projects[id] = p
+	return nil
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) CreateRevision(r *models.ProjectRevision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.createRevision(r)
+	return nil
+}
+
+func (s *Store) createRevision(r *models.ProjectRevision) {
+	r.ID = s.newID("project_revisions")
+	r.Revision = len(s.revisions[r.ProjectID]) + 1
+	r.CreatedAt = time.Now()
+	s.revisions[r.ProjectID] = append(s.revisions[r.ProjectID], *r)
+}
+
+func (s *Store) ListRevisions(projectID int) ([]models.ProjectRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.ProjectRevision{}, s.revisions[projectID]...), nil
+}
+
+func (s *Store) GetRevision(projectID, revision int) (*models.ProjectRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	revisions := s.revisions[projectID]
+	if revision < 1 || revision > len(revisions) {
+		return nil, store.ErrNotFound
+	}
+	r := revisions[revision-1]
+	return &r, nil
+}
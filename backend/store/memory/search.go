@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) ReplaceProjectTerms(projectID, orgID int, terms []models.SearchTerm) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searchTerms[projectID] = append([]models.SearchTerm{}, terms...)
+	return nil
+}
+
+func (s *Store) SearchProjects(orgID int, terms []string, limit int) ([]store.SearchHit, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var hits []store.SearchHit
+	for id, indexed := range s.searchTerms {
+		if p, ok := s.projects[id]; !ok || p.OrganisationID != orgID || p.DeletedAt != nil {
+			continue
+		}
+		score := 0
+		for _, term := range terms {
+			best := 0
+			for _, t := range indexed {
+				match := 0
+				if t.Term == term {
+					match = 2 * t.Weight
+				} else if strings.HasPrefix(t.Term, term) {
+					match = t.Weight
+				}
+				if match > best {
+					best = match
+				}
+			}
+			if best == 0 {
+				score = 0
+				break
+			}
+			score += best
+		}
+		if score > 0 {
+			hits = append(hits, store.SearchHit{ProjectID: id, Score: score})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ProjectID > hits[j].ProjectID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
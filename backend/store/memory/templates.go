@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) GetQuotationTemplate(orgID int) (*models.QuotationTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[orgID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	t.Notes = append([]string(nil), t.Notes...)
+	return &t, nil
+}
+
+func (s *Store) SaveQuotationTemplate(t *models.QuotationTemplate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.UpdatedAt = time.Now()
+	saved := *t
+	saved.Notes = append([]string(nil), t.Notes...)
+	s.templates[t.OrganisationID] = saved
+	return nil
+}
+
+func (s *Store) DeleteQuotationTemplate(orgID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.templates[orgID]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.templates, orgID)
+	return nil
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) CreateRefreshToken(t *models.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addRefreshToken(t)
+	return nil
+}
+
+// addRefreshToken stores a new token; callers must hold the write lock
+func (s *Store) addRefreshToken(t *models.RefreshToken) {
+	t.ID = s.newID("refresh_tokens")
+	t.CreatedAt = time.Now()
+	s.refreshTokens[t.TokenHash] = *t
+}
+
+func (s *Store) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.refreshTokens[tokenHash]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &t, nil
+}
+
+func (s *Store) RotateRefreshToken(oldID int, next *models.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, t := range s.refreshTokens {
+		if t.ID != oldID {
+			continue
+		}
+		if t.RevokedAt != nil {
+			return store.ErrConflict
+		}
+		now := time.Now()
+		t.RevokedAt = &now
+		s.refreshTokens[hash] = t
+		s.addRefreshToken(next)
+		return nil
+	}
+	return store.ErrConflict
+}
+
+func (s *Store) SessionActive(sessionID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now()
+	for _, t := range s.refreshTokens {
+		if t.SessionID == sessionID && t.RevokedAt == nil && t.ExpiresAt.After(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Store) RevokeSession(sessionID string) error {
+	s.revokeTokens(func(t models.RefreshToken) bool { return t.SessionID == sessionID })
+	return nil
+}
+
+func (s *Store) RevokeUserSessions(role string, userID int) (int, error) {
+	return s.revokeTokens(func(t models.RefreshToken) bool { return t.Role == role && t.UserID == userID }), nil
+}
+
+func (s *Store) RevokeOtherSessions(role string, userID int, keepSessionID string) (int, error) {
+	return s.revokeTokens(func(t models.RefreshToken) bool {
+		return t.Role == role && t.UserID == userID && t.SessionID != keepSessionID
+	}), nil
+}
+
+// revokeTokens revokes every unrevoked matching token and returns the number
+// of sessions that were still active
+func (s *Store) revokeTokens(match func(models.RefreshToken) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	sessions := make(map[string]bool)
+	for hash, t := range s.refreshTokens {
+		if t.RevokedAt != nil || !match(t) {
+			continue
+		}
+		if t.ExpiresAt.After(now) {
+			sessions[t.SessionID] = true
+		}
+		t.RevokedAt = &now
+		s.refreshTokens[hash] = t
+	}
+	return len(sessions)
+}
+
+func (s *Store) PurgeRefreshTokens(expiredBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for hash, t := range s.refreshTokens {
+		if t.ExpiresAt.Before(expiredBefore) {
+			delete(s.refreshTokens, hash)
+			n++
+		}
+	}
+	return n, nil
+}
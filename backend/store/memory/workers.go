@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) CreateWorker(w *models.Worker) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.workers {
+		if existing.Username == w.Username {
+			return store.ErrConflict
+		}
+	}
+	w.ID = s.newID("workers")
+	w.CreatedAt = time.Now()
+	s.workers[w.ID] = *w
+	return nil
+}
+
+func (s *Store) GetWorker(id, orgID int) (*models.Worker, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	w, ok := s.workers[id]
+	if !ok || w.DeletedAt != nil || w.OrganisationID != orgID {
+		return nil, store.ErrNotFound
+	}
+	return &w, nil
+}
+
+func (s *Store) GetWorkerByUsername(username string) (*models.Worker, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.workers {
+		if w.Username == username && w.DeletedAt == nil {
+			return &w, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+// WorkerUsernameExists also counts deleted workers, who keep their username
+func (s *Store) WorkerUsernameExists(username string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.workers {
+		if w.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Store) ListWorkers(orgID int, filter store.WorkerFilter) ([]models.Worker, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var workers []models.Worker
+	for _, w := range s.workers {
+		if w.OrganisationID == orgID && (w.DeletedAt != nil) == filter.Deleted {
+			workers = append(workers, w)
+		}
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].ID < workers[j].ID })
+	return workers, nil
+}
+
+func (s *Store) UpdateWorker(id, orgID int, update store.WorkerUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.workers[id]
+	if !ok || w.DeletedAt != nil || w.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	if update.Username != nil {
+		for _, other := range s.workers {
+			if other.ID != id && other.Username == *update.Username {
+				return store.ErrConflict
+			}
+		}
+		w.Username = *update.Username
+	}
+	if update.Name != nil {
+		w.Name = *update.Name
+	}
+	if update.Phone != nil {
+		w.Phone = *update.Phone
+	}
+	if update.IsActive != nil {
+		w.IsActive = *update.IsActive
+	}
+	s.workers[id] = w
+	return nil
+}
+
+func (s *Store) UpdateWorkerPassword(id, orgID int, passwordHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.workers[id]
+	if !ok || w.DeletedAt != nil || w.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	w.PasswordHash = passwordHash
+	s.workers[id] = w
+	return nil
+}
+
+func (s *Store) DeleteWorker(id, orgID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.workers[id]
+	if !ok || w.DeletedAt != nil || w.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	now := time.Now()
+	w.DeletedAt = &now
+	s.workers[id] = w
+	return nil
+}
+
+func (s *Store) RestoreWorker(id, orgID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.workers[id]
+	if !ok || w.DeletedAt == nil || w.OrganisationID != orgID {
+		return store.ErrNotFound
+	}
+	w.DeletedAt = nil
+	s.workers[id] = w
+	return nil
+}
+
+func (s *Store) PurgeWorkers(deletedBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hasProjects := make(map[int]bool)
+	for _, p := range s.projects {
+		hasProjects[p.WorkerID] = true
+	}
+	n := 0
+	for id, w := range s.workers {
+		if w.DeletedAt != nil && w.DeletedAt.Before(deletedBefore) && !hasProjects[id] {
+			delete(s.workers, id)
+			n++
+		}
+	}
+	return n, nil
+}
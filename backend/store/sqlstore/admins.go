@@ -0,0 +1,193 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+const adminColumns = `id, username, password_hash, organisation_id, role, created_at`
+
+func scanAdmin(row interface{ Scan(...interface{}) error }, a *models.Admin) error {
+	return row.Scan(&a.ID, &a.Username, &a.PasswordHash, &a.OrganisationID, &a.Role, &a.CreatedAt)
+}
+
+func (s *Store) CreateOrganisation(o *models.Organisation, owner *models.Admin) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := s.createOrganisation(tx, o, owner); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) CreateFirstOrganisation(o *models.Organisation, owner *models.Admin) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	// The table lock keeps a concurrent setup from creating an admin too
+	if err := s.lockTable(tx, "admins"); err != nil {
+		return err
+	}
+	var admins int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM admins`).Scan(&admins); err != nil {
+		return err
+	}
+	if admins > 0 {
+		return store.ErrConflict
+	}
+	if err := s.createOrganisation(tx, o, owner); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) createOrganisation(tx *sql.Tx, o *models.Organisation, owner *models.Admin) error {
+	// The table lock keeps a username checked as free from being taken
+	// before the owner is inserted
+	if err := s.lockTable(tx, "admins"); err != nil {
+		return err
+	}
+	q := s.dialect.Query(`SELECT COUNT(*) FROM admins WHERE username = ?`, owner.Username)
+	var taken int
+	if err := tx.QueryRow(q.SQL(), q.Args()...).Scan(&taken); err != nil {
+		return err
+	}
+	if taken > 0 {
+		return store.ErrConflict
+	}
+	q = s.dialect.InsertReturning("organisations", []string{"name", "created_at"}, []string{"id", "created_at"}, o.Name, Now)
+	if err := tx.QueryRow(q.SQL(), q.Args()...).Scan(&o.ID, &o.CreatedAt); err != nil {
+		return err
+	}
+	owner.OrganisationID = o.ID
+	q = s.insertAdmin(owner)
+	return tx.QueryRow(q.SQL(), q.Args()...).Scan(&owner.ID, &owner.CreatedAt)
+}
+
+func (s *Store) insertAdmin(a *models.Admin) *Query {
+	return s.dialect.InsertReturning("admins",
+		[]string{"username", "password_hash", "organisation_id", "role", "created_at"}, []string{"id", "created_at"},
+		a.Username, a.PasswordHash, a.OrganisationID, a.Role, Now)
+}
+
+func (s *Store) GetOrganisation(id int) (*models.Organisation, error) {
+	q := s.dialect.Query(`SELECT id, name, created_at FROM organisations WHERE id = ?`, id)
+	var o models.Organisation
+	if err := s.db.QueryRow(q.SQL(), q.Args()...).Scan(&o.ID, &o.Name, &o.CreatedAt); err != nil {
+		return nil, notFound(err)
+	}
+	return &o, nil
+}
+
+func (s *Store) UpdateOrganisation(id int, name string) error {
+	q := s.dialect.Query(`UPDATE organisations SET name = ? WHERE id = ?`, name, id)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) CreateAdmin(a *models.Admin) error {
+	q := s.dialect.Query(`SELECT COUNT(*) FROM admins WHERE username = ?`, a.Username)
+	var taken int
+	if err := s.db.QueryRow(q.SQL(), q.Args()...).Scan(&taken); err != nil {
+		return err
+	}
+	if taken > 0 {
+		return store.ErrConflict
+	}
+	q = s.insertAdmin(a)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&a.ID, &a.CreatedAt)
+}
+
+func (s *Store) GetAdmin(id int) (*models.Admin, error) {
+	return s.getAdmin(s.dialect.Query(`SELECT `+adminColumns+` FROM admins WHERE id = ?`, id))
+}
+
+func (s *Store) GetAdminByUsername(username string) (*models.Admin, error) {
+	return s.getAdmin(s.dialect.Query(`SELECT `+adminColumns+` FROM admins WHERE username = ?`, username))
+}
+
+func (s *Store) getAdmin(q *Query) (*models.Admin, error) {
+	var a models.Admin
+	if err := scanAdmin(s.db.QueryRow(q.SQL(), q.Args()...), &a); err != nil {
+		return nil, notFound(err)
+	}
+	return &a, nil
+}
+
+func (s *Store) ListAdmins(orgID int) ([]models.Admin, error) {
+	q := s.dialect.Query(`SELECT `+adminColumns+` FROM admins WHERE organisation_id = ? ORDER BY id`, orgID)
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var admins []models.Admin
+	for rows.Next() {
+		var a models.Admin
+		if err := scanAdmin(rows, &a); err != nil {
+			return nil, err
+		}
+		admins = append(admins, a)
+	}
+	return admins, rows.Err()
+}
+
+func (s *Store) UpdateAdminPassword(id int, passwordHash string) error {
+	q := s.dialect.Query(`UPDATE admins SET password_hash = ? WHERE id = ?`, passwordHash, id)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) UpdateAdminRole(id, orgID int, role string) error {
+	q := s.dialect.Query(`UPDATE admins SET role = ? WHERE id = ? AND organisation_id = ?`, role, id, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) DeleteAdmin(id, orgID int) error {
+	q := s.dialect.Query(`DELETE FROM admins WHERE id = ? AND organisation_id = ?`, id, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) CountAdmins() (int, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM admins`).Scan(&count)
+	return count, err
+}
+
+func (s *Store) CreateInvite(inv *models.Invite) error {
+	q := s.dialect.InsertReturning("invites", []string{"code_hash", "note", "expires_at", "created_at"}, []string{"id", "created_at"},
+		inv.CodeHash, inv.Note, inv.ExpiresAt, Now)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&inv.ID, &inv.CreatedAt)
+}
+
+func (s *Store) RedeemInvite(codeHash string, o *models.Organisation, owner *models.Admin) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	// Marking the invite used first locks it against a concurrent redeem
+	q := s.dialect.Query(`UPDATE invites SET used_at = ? WHERE code_hash = ? AND used_at IS NULL AND expires_at > ?`, Now, codeHash, time.Now())
+	if err := affected(tx.Exec(q.SQL(), q.Args()...)); err != nil {
+		return err
+	}
+	var inviteID int
+	q = s.dialect.Query(`SELECT id FROM invites WHERE code_hash = ?`, codeHash)
+	if err := tx.QueryRow(q.SQL(), q.Args()...).Scan(&inviteID); err != nil {
+		return err
+	}
+	if err := s.createOrganisation(tx, o, owner); err != nil {
+		return err
+	}
+	q = s.dialect.Query(`UPDATE invites SET organisation_id = ? WHERE id = ?`, o.ID, inviteID)
+	if _, err := tx.Exec(q.SQL(), q.Args()...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,80 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+// nullJSON stores an empty snapshot as NULL
+func nullJSON(raw json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(raw), Valid: len(raw) > 0}
+}
+
+func (s *Store) RecordAudit(e *models.AuditEntry) error {
+	q := s.dialect.InsertReturning("audit_log",
+		[]string{"organisation_id", "actor_role", "actor_id", "method", "route", "path", "entity_type", "entity_id", "status", "before_json", "after_json", "client_ip", "created_at"},
+		[]string{"id", "created_at"},
+		e.OrganisationID, e.ActorRole, e.ActorID, e.Method, e.Route, e.Path, e.EntityType, e.EntityID, e.Status,
+		nullJSON(e.Before), nullJSON(e.After), e.ClientIP, Now)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&e.ID, &e.CreatedAt)
+}
+
+func (s *Store) ListAudit(orgID int, q store.AuditQuery) ([]models.AuditEntry, int, error) {
+	where := s.dialect.Query(` WHERE organisation_id = ?`, orgID)
+	if q.EntityType != "" {
+		where.Write(` AND entity_type = ?`, q.EntityType)
+	}
+	if q.EntityID != nil {
+		where.Write(` AND entity_id = ?`, *q.EntityID)
+	}
+	if q.ActorRole != "" {
+		where.Write(` AND actor_role = ?`, q.ActorRole)
+	}
+	if q.ActorID != nil {
+		where.Write(` AND actor_id = ?`, *q.ActorID)
+	}
+	if q.From != nil {
+		where.Write(` AND created_at >= ?`, *q.From)
+	}
+	if q.To != nil {
+		where.Write(` AND created_at < ?`, *q.To)
+	}
+	count := s.dialect.Query(`SELECT COUNT(*) FROM audit_log`).Append(where)
+	var total int
+	if err := s.db.QueryRow(count.SQL(), count.Args()...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	list := s.dialect.Query(`SELECT id, organisation_id, actor_role, actor_id, method, route, path, entity_type, entity_id, status, before_json, after_json, client_ip, created_at FROM audit_log`).
+		Append(where).Write(` ORDER BY created_at DESC, id DESC`).Page(q.Limit, q.Offset)
+	rows, err := s.db.Query(list.SQL(), list.Args()...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		var entityID sql.NullInt64
+		var before, after sql.NullString
+		err := rows.Scan(&e.ID, &e.OrganisationID, &e.ActorRole, &e.ActorID, &e.Method, &e.Route, &e.Path, &e.EntityType, &entityID,
+			&e.Status, &before, &after, &e.ClientIP, &e.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		if entityID.Valid {
+			id := int(entityID.Int64)
+			e.EntityID = &id
+		}
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
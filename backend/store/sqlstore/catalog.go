@@ -0,0 +1,299 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+// Catalog implements store.CatalogStore on the brands, folders and cloths
+// tables of any Dialect
+type Catalog struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewCatalog returns the catalog repository of an open database
+func NewCatalog(db *sql.DB, d Dialect) *Catalog {
+	return &Catalog{db: db, dialect: d}
+}
+
+// exists runs a COUNT(*) query and reports whether it counted anything
+func (s *Catalog) exists(q *Query) (bool, error) {
+	var count int
+	err := s.db.QueryRow(q.SQL(), q.Args()...).Scan(&count)
+	return count > 0, err
+}
+
+func (s *Catalog) CreateBrand(b *models.Brand) error {
+	q := s.dialect.Insert("brands",
+		[]string{"name", "description", "logo_url", "organisation_id", "is_active", "created_at", "updated_at"},
+		b.Name, b.Description, b.LogoURL, b.OrganisationID, b.IsActive, b.CreatedAt, b.UpdatedAt)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&b.ID)
+}
+
+const brandColumns = `id, name, description, logo_url, organisation_id, is_active, created_at, updated_at`
+
+func scanBrand(row interface{ Scan(...interface{}) error }, b *models.Brand) error {
+	return row.Scan(&b.ID, &b.Name, &b.Description, &b.LogoURL, &b.OrganisationID, &b.IsActive, &b.CreatedAt, &b.UpdatedAt)
+}
+
+func (s *Catalog) ListBrands(orgID int, filter store.BrandFilter) ([]models.Brand, error) {
+	q := s.dialect.Query(`SELECT `+brandColumns+` FROM brands WHERE organisation_id = ?`, orgID)
+	if filter.Active != nil {
+		q.Write(" AND is_active = ?", *filter.Active)
+	}
+	q.Write(" ORDER BY name ASC")
+
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var brands []models.Brand
+	for rows.Next() {
+		var brand models.Brand
+		if err := scanBrand(rows, &brand); err != nil {
+			return nil, err
+		}
+		brands = append(brands, brand)
+	}
+	return brands, rows.Err()
+}
+
+func (s *Catalog) GetBrand(id, orgID int) (*models.Brand, error) {
+	q := s.dialect.Query(`SELECT `+brandColumns+` FROM brands WHERE id = ? AND organisation_id = ?`, id, orgID)
+	var brand models.Brand
+	if err := scanBrand(s.db.QueryRow(q.SQL(), q.Args()...), &brand); err != nil {
+		return nil, notFound(err)
+	}
+	return &brand, nil
+}
+
+func (s *Catalog) UpdateBrand(id, orgID int, update store.BrandUpdate) error {
+	q := s.dialect.Query("UPDATE brands SET updated_at = ?", time.Now())
+	if update.Name != nil {
+		q.Write(", name = ?", *update.Name)
+	}
+	if update.Description != nil {
+		q.Write(", description = ?", *update.Description)
+	}
+	if update.LogoURL != nil {
+		q.Write(", logo_url = ?", *update.LogoURL)
+	}
+	if update.IsActive != nil {
+		q.Write(", is_active = ?", *update.IsActive)
+	}
+	q.Write(" WHERE id = ? AND organisation_id = ?", id, orgID)
+
+	_, err := s.db.Exec(q.SQL(), q.Args()...)
+	return err
+}
+
+func (s *Catalog) DeleteBrand(id, orgID int) error {
+	q := s.dialect.Query("DELETE FROM brands WHERE id = ? AND organisation_id = ?", id, orgID)
+	_, err := s.db.Exec(q.SQL(), q.Args()...)
+	return err
+}
+
+func (s *Catalog) ActiveBrandExists(id, orgID int) (bool, error) {
+	return s.exists(s.dialect.Query("SELECT COUNT(*) FROM brands WHERE id = ? AND organisation_id = ? AND is_active = ?", id, orgID, true))
+}
+
+func (s *Catalog) BrandHasFolders(id int) (bool, error) {
+	return s.exists(s.dialect.Query("SELECT COUNT(*) FROM folders WHERE brand_id = ?", id))
+}
+
+func (s *Catalog) CreateFolder(f *models.Folder) error {
+	q := s.dialect.Insert("folders",
+		[]string{"name", "description", "brand_id", "organisation_id", "is_active", "created_at", "updated_at"},
+		f.Name, f.Description, f.BrandID, f.OrganisationID, f.IsActive, f.CreatedAt, f.UpdatedAt)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&f.ID)
+}
+
+const folderSelect = `
+		SELECT f.id, f.name, f.description, f.brand_id, f.organisation_id, f.is_active,
+		       f.created_at, f.updated_at, b.name as brand_name
+		FROM folders f
+		JOIN brands b ON f.brand_id = b.id
+`
+
+func scanFolder(row interface{ Scan(...interface{}) error }, f *models.FolderWithBrand) error {
+	return row.Scan(&f.ID, &f.Name, &f.Description, &f.BrandID,
+		&f.OrganisationID, &f.IsActive, &f.CreatedAt, &f.UpdatedAt,
+		&f.BrandName)
+}
+
+func (s *Catalog) ListFolders(orgID int, filter store.FolderFilter) ([]models.FolderWithBrand, error) {
+	q := s.dialect.Query(folderSelect+" WHERE f.organisation_id = ?", orgID)
+	if filter.BrandID != nil {
+		q.Write(" AND f.brand_id = ?", *filter.BrandID)
+	}
+	if filter.Active != nil {
+		q.Write(" AND f.is_active = ?", *filter.Active)
+	}
+	q.Write(" ORDER BY f.name ASC")
+
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []models.FolderWithBrand
+	for rows.Next() {
+		var folder models.FolderWithBrand
+		if err := scanFolder(rows, &folder); err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+	return folders, rows.Err()
+}
+
+func (s *Catalog) GetFolder(id, orgID int) (*models.FolderWithBrand, error) {
+	q := s.dialect.Query(folderSelect+" WHERE f.id = ? AND f.organisation_id = ?", id, orgID)
+	var folder models.FolderWithBrand
+	if err := scanFolder(s.db.QueryRow(q.SQL(), q.Args()...), &folder); err != nil {
+		return nil, notFound(err)
+	}
+	return &folder, nil
+}
+
+func (s *Catalog) UpdateFolder(id, orgID int, update store.FolderUpdate) error {
+	q := s.dialect.Query("UPDATE folders SET updated_at = ?", time.Now())
+	if update.Name != nil {
+		q.Write(", name = ?", *update.Name)
+	}
+	if update.Description != nil {
+		q.Write(", description = ?", *update.Description)
+	}
+	if update.BrandID != nil {
+		q.Write(", brand_id = ?", *update.BrandID)
+	}
+	if update.IsActive != nil {
+		q.Write(", is_active = ?", *update.IsActive)
+	}
+	q.Write(" WHERE id = ? AND organisation_id = ?", id, orgID)
+
+	_, err := s.db.Exec(q.SQL(), q.Args()...)
+	return err
+}
+
+func (s *Catalog) DeleteFolder(id, orgID int) error {
+	q := s.dialect.Query("DELETE FROM folders WHERE id = ? AND organisation_id = ?", id, orgID)
+	_, err := s.db.Exec(q.SQL(), q.Args()...)
+	return err
+}
+
+func (s *Catalog) ActiveFolderExists(id, orgID int) (bool, error) {
+	return s.exists(s.dialect.Query("SELECT COUNT(*) FROM folders WHERE id = ? AND organisation_id = ? AND is_active = ?", id, orgID, true))
+}
+
+func (s *Catalog) FolderInBrand(folderID, brandID int) (bool, error) {
+	return s.exists(s.dialect.Query("SELECT COUNT(*) FROM folders WHERE id = ? AND brand_id = ?", folderID, brandID))
+}
+
+func (s *Catalog) FolderHasCloths(id int) (bool, error) {
+	return s.exists(s.dialect.Query("SELECT COUNT(*) FROM cloths WHERE folder_id = ?", id))
+}
+
+func (s *Catalog) CreateCloth(cl *models.Cloth) error {
+	q := s.dialect.Insert("cloths",
+		[]string{"name", "rate", "description", "image_url", "folder_id", "brand_id", "organisation_id", "is_active", "created_at", "updated_at"},
+		cl.Name, cl.Rate, cl.Description, cl.ImageURL, cl.FolderID, cl.BrandID, cl.OrganisationID, cl.IsActive, cl.CreatedAt, cl.UpdatedAt)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&cl.ID)
+}
+
+const clothSelect = `
+		SELECT c.id, c.name, c.rate, c.description, c.image_url, c.folder_id, c.brand_id,
+		       c.organisation_id, c.is_active, c.created_at, c.updated_at,
+		       f.name as folder_name, b.name as brand_name
+		FROM cloths c
+		JOIN folders f ON c.folder_id = f.id
+		JOIN brands b ON c.brand_id = b.id
+`
+
+func scanCloth(row interface{ Scan(...interface{}) error }, cl *models.ClothWithDetails) error {
+	return row.Scan(
+		&cl.ID, &cl.Name, &cl.Rate, &cl.Description, &cl.ImageURL,
+		&cl.FolderID, &cl.BrandID, &cl.OrganisationID, &cl.IsActive,
+		&cl.CreatedAt, &cl.UpdatedAt, &cl.FolderName, &cl.BrandName)
+}
+
+func (s *Catalog) ListCloths(orgID int, filter store.ClothFilter) ([]models.ClothWithDetails, error) {
+	q := s.dialect.Query(clothSelect+" WHERE c.organisation_id = ?", orgID)
+	if filter.BrandID != nil {
+		q.Write(" AND c.brand_id = ?", *filter.BrandID)
+	}
+	if filter.FolderID != nil {
+		q.Write(" AND c.folder_id = ?", *filter.FolderID)
+	}
+	if filter.Active != nil {
+		q.Write(" AND c.is_active = ?", *filter.Active)
+	}
+	q.Write(" ORDER BY c.name ASC")
+
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cloths []models.ClothWithDetails
+	for rows.Next() {
+		var cloth models.ClothWithDetails
+		if err := scanCloth(rows, &cloth); err != nil {
+			return nil, err
+		}
+		cloths = append(cloths, cloth)
+	}
+	return cloths, rows.Err()
+}
+
+func (s *Catalog) GetCloth(id, orgID int) (*models.ClothWithDetails, error) {
+	q := s.dialect.Query(clothSelect+" WHERE c.id = ? AND c.organisation_id = ?", id, orgID)
+	var cloth models.ClothWithDetails
+	if err := scanCloth(s.db.QueryRow(q.SQL(), q.Args()...), &cloth); err != nil {
+		return nil, notFound(err)
+	}
+	return &cloth, nil
+}
+
+func (s *Catalog) UpdateCloth(id, orgID int, update store.ClothUpdate) error {
+	q := s.dialect.Query("UPDATE cloths SET updated_at = ?", time.Now())
+	if update.Name != nil {
+		q.Write(", name = ?", *update.Name)
+	}
+	if update.Rate != nil {
+		q.Write(", rate = ?", *update.Rate)
+	}
+	if update.Description != nil {
+		q.Write(", description = ?", *update.Description)
+	}
+	if update.ImageURL != nil {
+		q.Write(", image_url = ?", *update.ImageURL)
+	}
+	if update.FolderID != nil {
+		q.Write(", folder_id = ?", *update.FolderID)
+	}
+	if update.BrandID != nil {
+		q.Write(", brand_id = ?", *update.BrandID)
+	}
+	if update.IsActive != nil {
+		q.Write(", is_active = ?", *update.IsActive)
+	}
+	q.Write(" WHERE id = ? AND organisation_id = ?", id, orgID)
+
+	_, err := s.db.Exec(q.SQL(), q.Args()...)
+	return err
+}
+
+func (s *Catalog) DeleteCloth(id, orgID int) error {
+	q := s.dialect.Query("DELETE FROM cloths WHERE id = ? AND organisation_id = ?", id, orgID)
+	_, err := s.db.Exec(q.SQL(), q.Args()...)
+	return err
+}
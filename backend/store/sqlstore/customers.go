@@ -0,0 +1,72 @@
+package sqlstore
+
+import (
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+const customerColumns = `id, organisation_id, name, phone, normalized_phone, address, merged_into_id, created_at, updated_at`
+
+func scanCustomer(row interface{ Scan(...interface{}) error }, c *models.Customer) error {
+	return row.Scan(&c.ID, &c.OrganisationID, &c.Name, &c.Phone, &c.NormalizedPhone, &c.Address, &c.MergedIntoID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (s *Store) SaveCustomer(c *models.Customer) error {
+	q := s.dialect.InsertMissing("customers", []string{"organisation_id", "normalized_phone"},
+		[]string{"organisation_id", "normalized_phone", "name", "phone", "address", "created_at", "updated_at"},
+		c.OrganisationID, c.NormalizedPhone, c.Name, c.Phone, c.Address, Now, Now)
+	if _, err := s.db.Exec(q.SQL(), q.Args()...); err != nil {
+		return err
+	}
+	q = s.dialect.Query(`UPDATE customers SET name = ?, phone = ?, address = ?, updated_at = ? WHERE organisation_id = ? AND normalized_phone = ? AND merged_into_id IS NULL`,
+		c.Name, c.Phone, c.Address, Now, c.OrganisationID, c.NormalizedPhone)
+	if _, err := s.db.Exec(q.SQL(), q.Args()...); err != nil {
+		return err
+	}
+	q = s.dialect.Query(`SELECT `+customerColumns+` FROM customers WHERE id = (SELECT COALESCE(merged_into_id, id) FROM customers WHERE organisation_id = ? AND normalized_phone = ?)`,
+		c.OrganisationID, c.NormalizedPhone)
+	return scanCustomer(s.db.QueryRow(q.SQL(), q.Args()...), c)
+}
+
+func (s *Store) GetCustomer(id, orgID int) (*models.Customer, error) {
+	var c models.Customer
+	q := s.dialect.Query(`SELECT `+customerColumns+` FROM customers WHERE id = ? AND organisation_id = ? AND merged_into_id IS NULL`, id, orgID)
+	if err := scanCustomer(s.db.QueryRow(q.SQL(), q.Args()...), &c); err != nil {
+		return nil, notFound(err)
+	}
+	return &c, nil
+}
+
+func (s *Store) SetProjectCustomer(projectID int, customerID *int) error {
+	q := s.dialect.Query(`UPDATE projects SET customer_id = ? WHERE id = ?`, customerID, projectID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) MergeCustomers(orgID, intoID, fromID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Touching both customers locks them so neither is merged elsewhere in
+	// the meantime
+	q := s.dialect.Query(`UPDATE customers SET updated_at = ? WHERE id IN (?, ?) AND organisation_id = ? AND merged_into_id IS NULL`,
+		Now, intoID, fromID, orgID)
+	count, err := rowsAffected(tx.Exec(q.SQL(), q.Args()...))
+	if err != nil {
+		return err
+	}
+	if count != 2 {
+		return store.ErrNotFound
+	}
+	q = s.dialect.Query(`UPDATE projects SET customer_id = ? WHERE customer_id = ?`, intoID, fromID)
+	if _, err := tx.Exec(q.SQL(), q.Args()...); err != nil {
+		return err
+	}
+	q = s.dialect.Query(`UPDATE customers SET merged_into_id = ?, updated_at = ? WHERE id = ? OR merged_into_id = ?`, intoID, Now, fromID, fromID)
+	if _, err := tx.Exec(q.SQL(), q.Args()...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,246 @@
+// Package sqlstore holds the repositories of every SQL database the stores
+// run on, written once, and the Dialect that papers over the databases'
+// differences: parameter placeholders, how a statement returns the columns it
+// wrote, paging, upserts and table locks.
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+// Dialect is the SQL flavour of a database
+type Dialect int
+
+const (
+	// MSSQL is SQL Server and Azure SQL: @p1 placeholders, OUTPUT INSERTED
+	MSSQL Dialect = iota
+	// Postgres uses $1 placeholders and RETURNING
+	Postgres
+)
+
+// Placeholder returns the marker for the nth (1-based) query parameter
+func (d Dialect) Placeholder(n int) string {
+	if d == MSSQL {
+		return "@p" + strconv.Itoa(n)
+	}
+	return "$" + strconv.Itoa(n)
+}
+
+// Query starts a query. In sql, and in everything later written to the query,
+// each ? stands for the next argument and is replaced with the dialect's
+// placeholder, so the text itself must not contain a literal ?.
+func (d Dialect) Query(sql string, args ...interface{}) *Query {
+	q := &Query{dialect: d}
+	return q.Write(sql, args...)
+}
+
+// Insert builds an insert of one row into table that returns the generated
+// id column, to be run with QueryRow and scanned into the new id
+func (d Dialect) Insert(table string, columns []string, values ...interface{}) *Query {
+	return d.InsertReturning(table, columns, []string{"id"}, values...)
+}
+
+// InsertReturning builds an insert of one row into table that returns the
+// returning columns as they were stored, to be run with QueryRow
+func (d Dialect) InsertReturning(table string, columns, returning []string, values ...interface{}) *Query {
+	marks := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	if d == MSSQL {
+		inserted := make([]string, len(returning))
+		for i, c := range returning {
+			inserted[i] = "INSERTED." + c
+		}
+		return d.Query(fmt.Sprintf("INSERT INTO %s (%s) OUTPUT %s VALUES (%s)", table, strings.Join(columns, ", "), strings.Join(inserted, ", "), marks), values...)
+	}
+	return d.Query(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", table, strings.Join(columns, ", "), marks, strings.Join(returning, ", ")), values...)
+}
+
+// Upsert builds an insert of one row into table that, when a row with the
+// same key columns exists, updates the row's other columns instead. The
+// key columns need a unique index.
+func (d Dialect) Upsert(table string, keys, columns []string, values ...interface{}) *Query {
+	return d.merge(table, keys, columns, true, values)
+}
+
+// InsertMissing builds an insert of one row into table that does nothing
+// when a row with the same key columns exists. The key columns need a unique
+// index.
+func (d Dialect) InsertMissing(table string, keys, columns []string, values ...interface{}) *Query {
+	return d.merge(table, keys, columns, false, values)
+}
+
+func (d Dialect) merge(table string, keys, columns []string, update bool, values []interface{}) *Query {
+	var set []string
+	for _, c := range columns {
+		if !slices.Contains(keys, c) {
+			set = append(set, c)
+		}
+	}
+	if d == MSSQL {
+		// HOLDLOCK keeps a concurrent merge from inserting the same key
+		source := make([]string, len(columns))
+		inserted := make([]string, len(columns))
+		for i, c := range columns {
+			source[i] = "? AS " + c
+			inserted[i] = "source." + c
+		}
+		on := make([]string, len(keys))
+		for i, k := range keys {
+			on[i] = fmt.Sprintf("target.%s = source.%s", k, k)
+		}
+		sql := fmt.Sprintf("MERGE %s WITH (HOLDLOCK) AS target USING (SELECT %s) AS source ON %s",
+			table, strings.Join(source, ", "), strings.Join(on, " AND "))
+		if update && len(set) > 0 {
+			for i, c := range set {
+				set[i] = fmt.Sprintf("%s = source.%s", c, c)
+			}
+			sql += " WHEN MATCHED THEN UPDATE SET " + strings.Join(set, ", ")
+		}
+		sql += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);", strings.Join(columns, ", "), strings.Join(inserted, ", "))
+		return d.Query(sql, values...)
+	}
+	marks := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s)", table, strings.Join(columns, ", "), marks, strings.Join(keys, ", "))
+	if update && len(set) > 0 {
+		for i, c := range set {
+			set[i] = fmt.Sprintf("%s = EXCLUDED.%s", c, c)
+		}
+		sql += " DO UPDATE SET " + strings.Join(set, ", ")
+	} else {
+		sql += " DO NOTHING"
+	}
+	return d.Query(sql, values...)
+}
+
+// LockTable returns a statement that keeps other transactions from writing
+// to table, while still letting them read it, until the transaction that ran
+// it ends
+func (d Dialect) LockTable(table string) string {
+	if d == MSSQL {
+		return "SELECT COUNT(*) FROM " + table + " WITH (TABLOCK, UPDLOCK, HOLDLOCK)"
+	}
+	return "LOCK TABLE " + table + " IN SHARE ROW EXCLUSIVE MODE"
+}
+
+// Now is the current time of the database, for use as a query argument
+const Now Expr = "CURRENT_TIMESTAMP"
+
+// Expr is SQL passed as a query argument. It is written into the query text
+// in place of its ? instead of being sent as a parameter.
+type Expr string
+
+// Query is SQL text with its arguments, built up in pieces
+type Query struct {
+	dialect Dialect
+	// sql keeps a ? for each argument until SQL writes the placeholders
+	sql  strings.Builder
+	args []interface{}
+}
+
+// Write appends sql with the arguments for its ? marks. It panics if their
+// numbers differ, which is a mistake in the calling code.
+func (q *Query) Write(sql string, args ...interface{}) *Query {
+	parts := strings.Split(sql, "?")
+	if len(parts)-1 != len(args) {
+		panic(fmt.Sprintf("sqlstore: %d placeholders but %d arguments in %q", len(parts)-1, len(args), sql))
+	}
+	for i, part := range parts {
+		q.sql.WriteString(part)
+		if i >= len(args) {
+			continue
+		}
+		if expr, ok := args[i].(Expr); ok {
+			q.sql.WriteString(string(expr))
+			continue
+		}
+		q.args = append(q.args, args[i])
+		q.sql.WriteString("?")
+	}
+	return q
+}
+
+// Append appends another query, such as conditions shared by a count and a
+// list, with its arguments
+func (q *Query) Append(other *Query) *Query {
+	q.sql.WriteString(other.sql.String())
+	q.args = append(q.args, other.args...)
+	return q
+}
+
+// Page appends the paging of an ordered query: up to limit rows after the
+// first offset
+func (q *Query) Page(limit, offset int) *Query {
+	if q.dialect == MSSQL {
+		return q.Write(" OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", offset, limit)
+	}
+	return q.Write(" LIMIT ? OFFSET ?", limit, offset)
+}
+
+// SQL returns the query text with the dialect's placeholders
+func (q *Query) SQL() string {
+	var b strings.Builder
+	n := 0
+	for _, r := range q.sql.String() {
+		if r == '?' {
+			n++
+			b.WriteString(q.dialect.Placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Args returns the query arguments in placeholder order
+func (q *Query) Args() []interface{} { return q.args }
+
+// Contains returns a LIKE pattern, to be used with ESCAPE '\', that matches
+// any text containing s
+func Contains(s string) string {
+	return "%" + StartsWith(s)
+}
+
+// StartsWith returns a LIKE pattern, to be used with ESCAPE '\', that matches
+// any text starting with s
+func StartsWith(s string) string {
+	return likeEscaper.Replace(s) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `[`, `\[`)
+
+// affected maps a zero-row update or delete to store.ErrNotFound
+func affected(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// rowsAffected returns how many rows a statement changed
+func rowsAffected(res sql.Result, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// notFound maps sql.ErrNoRows to store.ErrNotFound
+func notFound(err error) error {
+	if err == sql.ErrNoRows {
+		return store.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,144 @@
+package sqlstore_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Vanaraj10/interior-backend/store/sqlstore"
+)
+
+// The tests in this file check the SQL each dialect writes, so they run
+// without a database
+
+func wantQuery(t *testing.T, what string, q *sqlstore.Query, sql string, args ...interface{}) {
+	t.Helper()
+	if got := q.SQL(); got != sql {
+		t.Errorf("%s SQL:\n got %s\nwant %s", what, got, sql)
+	}
+	if got := q.Args(); !reflect.DeepEqual(got, args) {
+		t.Errorf("%s args = %v, want %v", what, got, args)
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect sqlstore.Dialect
+		sql     string
+		args    []interface{}
+	}{
+		{"MSSQL", sqlstore.MSSQL,
+			`SELECT id FROM projects WHERE organisation_id = @p1 AND updated_at < CURRENT_TIMESTAMP AND status = @p2 AND worker_id = @p3 ORDER BY id OFFSET @p4 ROWS FETCH NEXT @p5 ROWS ONLY`,
+			[]interface{}{3, "quoted", 7, 20, 10}},
+		{"Postgres", sqlstore.Postgres,
+			`SELECT id FROM projects WHERE organisation_id = $1 AND updated_at < CURRENT_TIMESTAMP AND status = $2 AND worker_id = $3 ORDER BY id LIMIT $4 OFFSET $5`,
+			[]interface{}{3, "quoted", 7, 10, 20}},
+	}
+	for _, tt := range tests {
+		where := tt.dialect.Query(` AND worker_id = ?`, 7)
+		q := tt.dialect.Query(`SELECT id FROM projects WHERE organisation_id = ? AND updated_at < ?`, 3, sqlstore.Now).
+			Write(` AND status = ?`, "quoted").
+			Append(where).
+			Write(` ORDER BY id`).
+			Page(10, 20)
+		wantQuery(t, tt.name+" query", q, tt.sql, tt.args...)
+	}
+	if got := sqlstore.MSSQL.Placeholder(12); got != "@p12" {
+		t.Errorf("MSSQL placeholder 12 = %s", got)
+	}
+	if got := sqlstore.Postgres.Placeholder(12); got != "$12" {
+		t.Errorf("Postgres placeholder 12 = %s", got)
+	}
+}
+
+func TestWriteArgumentCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Write with fewer arguments than placeholders did not panic")
+		}
+	}()
+	sqlstore.Postgres.Query(`SELECT id FROM projects WHERE id = ? AND worker_id = ?`, 1)
+}
+
+func TestInsert(t *testing.T) {
+	columns := []string{"client_name", "worker_id", "created_at"}
+	wantQuery(t, "MSSQL Insert", sqlstore.MSSQL.Insert("projects", columns, "Anitha", 4, sqlstore.Now),
+		`INSERT INTO projects (client_name, worker_id, created_at) OUTPUT INSERTED.id VALUES (@p1, @p2, CURRENT_TIMESTAMP)`, "Anitha", 4)
+	wantQuery(t, "Postgres Insert", sqlstore.Postgres.Insert("projects", columns, "Anitha", 4, sqlstore.Now),
+		`INSERT INTO projects (client_name, worker_id, created_at) VALUES ($1, $2, CURRENT_TIMESTAMP) RETURNING id`, "Anitha", 4)
+
+	returning := []string{"id", "status", "created_at"}
+	wantQuery(t, "MSSQL InsertReturning", sqlstore.MSSQL.InsertReturning("projects", columns, returning, "Anitha", 4, sqlstore.Now),
+		`INSERT INTO projects (client_name, worker_id, created_at) OUTPUT INSERTED.id, INSERTED.status, INSERTED.created_at VALUES (@p1, @p2, CURRENT_TIMESTAMP)`, "Anitha", 4)
+	wantQuery(t, "Postgres InsertReturning", sqlstore.Postgres.InsertReturning("projects", columns, returning, "Anitha", 4, sqlstore.Now),
+		`INSERT INTO projects (client_name, worker_id, created_at) VALUES ($1, $2, CURRENT_TIMESTAMP) RETURNING id, status, created_at`, "Anitha", 4)
+}
+
+func TestUpsert(t *testing.T) {
+	keys := []string{"organisation_id"}
+	columns := []string{"organisation_id", "header", "footer"}
+	wantQuery(t, "MSSQL Upsert", sqlstore.MSSQL.Upsert("quotation_templates", keys, columns, 3, "head", "foot"),
+		`MERGE quotation_templates WITH (HOLDLOCK) AS target USING (SELECT @p1 AS organisation_id, @p2 AS header, @p3 AS footer) AS source ON target.organisation_id = source.organisation_id`+
+			` WHEN MATCHED THEN UPDATE SET header = source.header, footer = source.footer`+
+			` WHEN NOT MATCHED THEN INSERT (organisation_id, header, footer) VALUES (source.organisation_id, source.header, source.footer);`,
+		3, "head", "foot")
+	wantQuery(t, "Postgres Upsert", sqlstore.Postgres.Upsert("quotation_templates", keys, columns, 3, "head", "foot"),
+		`INSERT INTO quotation_templates (organisation_id, header, footer) VALUES ($1, $2, $3) ON CONFLICT (organisation_id) DO UPDATE SET header = EXCLUDED.header, footer = EXCLUDED.footer`,
+		3, "head", "foot")
+
+	// With only key columns there is nothing to update
+	wantQuery(t, "Postgres Upsert of keys", sqlstore.Postgres.Upsert("links", []string{"a", "b"}, []string{"a", "b"}, 1, 2),
+		`INSERT INTO links (a, b) VALUES ($1, $2) ON CONFLICT (a, b) DO NOTHING`, 1, 2)
+	wantQuery(t, "MSSQL Upsert of keys", sqlstore.MSSQL.Upsert("links", []string{"a", "b"}, []string{"a", "b"}, 1, 2),
+		`MERGE links WITH (HOLDLOCK) AS target USING (SELECT @p1 AS a, @p2 AS b) AS source ON target.a = source.a AND target.b = source.b`+
+			` WHEN NOT MATCHED THEN INSERT (a, b) VALUES (source.a, source.b);`, 1, 2)
+}
+
+func TestInsertMissing(t *testing.T) {
+	keys := []string{"organisation_id", "normalized_phone"}
+	columns := []string{"organisation_id", "normalized_phone", "name"}
+	wantQuery(t, "MSSQL InsertMissing", sqlstore.MSSQL.InsertMissing("customers", keys, columns, 3, "9876543210", "Anitha"),
+		`MERGE customers WITH (HOLDLOCK) AS target USING (SELECT @p1 AS organisation_id, @p2 AS normalized_phone, @p3 AS name) AS source`+
+			` ON target.organisation_id = source.organisation_id AND target.normalized_phone = source.normalized_phone`+
+			` WHEN NOT MATCHED THEN INSERT (organisation_id, normalized_phone, name) VALUES (source.organisation_id, source.normalized_phone, source.name);`,
+		3, "9876543210", "Anitha")
+	wantQuery(t, "Postgres InsertMissing", sqlstore.Postgres.InsertMissing("customers", keys, columns, 3, "9876543210", "Anitha"),
+		`INSERT INTO customers (organisation_id, normalized_phone, name) VALUES ($1, $2, $3) ON CONFLICT (organisation_id, normalized_phone) DO NOTHING`,
+		3, "9876543210", "Anitha")
+}
+
+func TestLockTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect sqlstore.Dialect
+		want    string
+	}{
+		{"MSSQL", sqlstore.MSSQL, `SELECT COUNT(*) FROM admins WITH (TABLOCK, UPDLOCK, HOLDLOCK)`},
+		{"Postgres", sqlstore.Postgres, `LOCK TABLE admins IN SHARE ROW EXCLUSIVE MODE`},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.LockTable("admins"); got != tt.want {
+			t.Errorf("%s LockTable = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLikePatterns(t *testing.T) {
+	tests := []struct {
+		s, contains, startsWith string
+	}{
+		{"2345", `%2345%`, `2345%`},
+		{"50%", `%50\%%`, `50\%%`},
+		{"an_", `%an\_%`, `an\_%`},
+		{`a\b`, `%a\\b%`, `a\\b%`},
+		{"[x]", `%\[x]%`, `\[x]%`},
+	}
+	for _, tt := range tests {
+		if got := sqlstore.Contains(tt.s); got != tt.contains {
+			t.Errorf("Contains(%q) = %s, want %s", tt.s, got, tt.contains)
+		}
+		if got := sqlstore.StartsWith(tt.s); got != tt.startsWith {
+			t.Errorf("StartsWith(%q) = %s, want %s", tt.s, got, tt.startsWith)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) RecordFailedLogin(f *models.FailedLogin) error {
+	q := s.dialect.InsertReturning("failed_logins",
+		[]string{"organisation_id", "role", "username", "user_id", "reason", "client_ip", "created_at"}, []string{"id", "created_at"},
+		f.OrganisationID, f.Role, f.Username, f.UserID, f.Reason, f.ClientIP, Now)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&f.ID, &f.CreatedAt)
+}
+
+func (s *Store) ListFailedLogins(orgID int, q store.FailedLoginQuery) ([]models.FailedLogin, int, error) {
+	where := s.dialect.Query(` WHERE organisation_id = ?`, orgID)
+	if q.Role != "" {
+		where.Write(` AND role = ?`, q.Role)
+	}
+	if q.Username != "" {
+		where.Write(` AND username = ?`, q.Username)
+	}
+	count := s.dialect.Query(`SELECT COUNT(*) FROM failed_logins`).Append(where)
+	var total int
+	if err := s.db.QueryRow(count.SQL(), count.Args()...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	list := s.dialect.Query(`SELECT id, organisation_id, role, username, user_id, reason, client_ip, created_at FROM failed_logins`).
+		Append(where).Write(` ORDER BY created_at DESC, id DESC`).Page(q.Limit, q.Offset)
+	rows, err := s.db.Query(list.SQL(), list.Args()...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	logins := []models.FailedLogin{}
+	for rows.Next() {
+		var f models.FailedLogin
+		var org, user sql.NullInt64
+		if err := rows.Scan(&f.ID, &org, &f.Role, &f.Username, &user, &f.Reason, &f.ClientIP, &f.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		if org.Valid {
+			id := int(org.Int64)
+			f.OrganisationID = &id
+		}
+		if user.Valid {
+			id := int(user.Int64)
+			f.UserID = &id
+		}
+		logins = append(logins, f)
+	}
+	return logins, total, rows.Err()
+}
@@ -0,0 +1,99 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Vanaraj10/interior-backend/models"
+)
+
+const measurementColumns = `m.id, m.project_id, m.room_id, COALESCE(r.room_key, ''), m.position, m.interior_type, m.room_label, m.model,
+	m.width, m.height, m.sqft, m.parts, m.rolls, m.meters, m.material_rate, m.labour_rate, m.material_cost, m.labour_cost, m.hardware_cost, m.total`
+
+func (s *Store) ReplaceProjectMeasurements(projectID int, rooms []models.Room, measurements []models.Measurement) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Measurements go first: they reference rooms without a cascade
+	for _, table := range []string{"measurements", "rooms"} {
+		q := s.dialect.Query(`DELETE FROM `+table+` WHERE project_id = ?`, projectID)
+		if _, err := tx.Exec(q.SQL(), q.Args()...); err != nil {
+			return err
+		}
+	}
+
+	roomIDs := make(map[string]int)
+	for i := range rooms {
+		r := &rooms[i]
+		r.ProjectID = projectID
+		q := s.dialect.Insert("rooms", []string{"project_id", "room_key", "name", "type"}, projectID, r.RoomKey, r.Name, r.Type)
+		if err := tx.QueryRow(q.SQL(), q.Args()...).Scan(&r.ID); err != nil {
+			return err
+		}
+		roomIDs[r.RoomKey] = r.ID
+	}
+
+	for i := range measurements {
+		m := &measurements[i]
+		m.ProjectID = projectID
+		m.RoomID = nil
+		if id, ok := roomIDs[m.RoomKey]; ok {
+			m.RoomID = &id
+		}
+		q := s.dialect.Insert("measurements",
+			[]string{"project_id", "room_id", "position", "interior_type", "room_label", "model", "width", "height", "sqft", "parts", "rolls", "meters",
+				"material_rate", "labour_rate", "material_cost", "labour_cost", "hardware_cost", "total"},
+			projectID, m.RoomID, m.Position, m.InteriorType, m.RoomLabel, m.Model, m.Width, m.Height, m.Sqft, m.Parts, m.Rolls, m.Meters,
+			m.MaterialRate, m.LabourRate, m.MaterialCost, m.LabourCost, m.HardwareCost, m.Total)
+		if err := tx.QueryRow(q.SQL(), q.Args()...).Scan(&m.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) ListProjectMeasurements(projectID int) ([]models.Room, []models.Measurement, error) {
+	q := s.dialect.Query(`SELECT id, project_id, room_key, name, type FROM rooms WHERE project_id = ? ORDER BY id`, projectID)
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	rooms := []models.Room{}
+	for rows.Next() {
+		var r models.Room
+		if err := rows.Scan(&r.ID, &r.ProjectID, &r.RoomKey, &r.Name, &r.Type); err != nil {
+			return nil, nil, err
+		}
+		rooms = append(rooms, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	q = s.dialect.Query(`SELECT `+measurementColumns+` FROM measurements m LEFT JOIN rooms r ON r.id = m.room_id WHERE m.project_id = ? ORDER BY m.position`, projectID)
+	rows, err = s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	measurements := []models.Measurement{}
+	for rows.Next() {
+		var m models.Measurement
+		var roomID sql.NullInt64
+		err := rows.Scan(&m.ID, &m.ProjectID, &roomID, &m.RoomKey, &m.Position, &m.InteriorType, &m.RoomLabel, &m.Model,
+			&m.Width, &m.Height, &m.Sqft, &m.Parts, &m.Rolls, &m.Meters, &m.MaterialRate, &m.LabourRate,
+			&m.MaterialCost, &m.LabourCost, &m.HardwareCost, &m.Total)
+		if err != nil {
+			return nil, nil, err
+		}
+		if roomID.Valid {
+			id := int(roomID.Int64)
+			m.RoomID = &id
+		}
+		measurements = append(measurements, m)
+	}
+	return rooms, measurements, rows.Err()
+}
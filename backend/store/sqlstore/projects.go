@@ -0,0 +1,255 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/lifecycle"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+const projectColumns = `id, client_name, phone, address, html, raw_data, worker_id, organisation_id, customer_id, is_completed, status, created_at, updated_at, deleted_at`
+
+func scanProject(row interface{ Scan(...interface{}) error }, p *models.Project) error {
+	return row.Scan(&p.ID, &p.ClientName, &p.Phone, &p.Address, &p.HTML, &p.RawData, &p.WorkerID, &p.OrganisationID, &p.CustomerID, &p.IsCompleted, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+}
+
+func (s *Store) CreateProject(p *models.Project) error {
+	return s.createProject(s.db, p)
+}
+
+func (s *Store) createProject(db execer, p *models.Project) error {
+	q := s.dialect.InsertReturning("projects",
+		[]string{"client_name", "phone", "address", "html", "raw_data", "worker_id", "organisation_id", "is_completed", "created_at", "updated_at"},
+		[]string{"id", "status", "created_at", "updated_at", "deleted_at"},
+		p.ClientName, p.Phone, p.Address, p.HTML, p.RawData, p.WorkerID, p.OrganisationID, false, Now, Now)
+	return db.QueryRow(q.SQL(), q.Args()...).Scan(&p.ID, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+}
+
+func (s *Store) UpdateProject(p *models.Project) error {
+	return s.updateProject(s.db, p)
+}
+
+func (s *Store) updateProject(db execer, p *models.Project) error {
+	q := s.dialect.Query(`UPDATE projects SET client_name = ?, phone = ?, address = ?, html = ?, raw_data = ?, updated_at = ? WHERE id = ? AND worker_id = ? AND organisation_id = ? AND deleted_at IS NULL`,
+		p.ClientName, p.Phone, p.Address, p.HTML, p.RawData, Now, p.ID, p.WorkerID, p.OrganisationID)
+	return affected(db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) SaveProject(p *models.Project, r *models.ProjectRevision) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if p.ID > 0 {
+		err = s.updateProject(tx, p)
+	} else {
+		err = s.createProject(tx, p)
+	}
+	if err != nil {
+		return err
+	}
+	r.ProjectID = p.ID
+	if err := s.insertRevision(tx, r); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) GetProject(id, orgID int) (*models.Project, error) {
+	return s.getProject(s.dialect.Query(`SELECT `+projectColumns+` FROM projects WHERE id = ? AND organisation_id = ? AND deleted_at IS NULL`, id, orgID))
+}
+
+func (s *Store) getProject(q *Query) (*models.Project, error) {
+	var p models.Project
+	if err := scanProject(s.db.QueryRow(q.SQL(), q.Args()...), &p); err != nil {
+		return nil, notFound(err)
+	}
+	return &p, nil
+}
+
+// projectSortColumns are the columns ListProjectsByOrganisation orders by
+var projectSortColumns = map[store.ProjectSort]string{
+	store.SortCreatedAt:  "created_at",
+	store.SortUpdatedAt:  "updated_at",
+	store.SortClientName: "client_name",
+	store.SortStatus:     "status",
+}
+
+// projectListColumns are projectColumns without the html
+const projectListColumns = `id, client_name, phone, address, '', raw_data, worker_id, organisation_id, customer_id, is_completed, status, created_at, updated_at, deleted_at`
+
+func (s *Store) ListProjectsByOrganisation(orgID int, q store.ProjectQuery) ([]models.Project, store.ProjectCounts, error) {
+	where := s.dialect.Query(` WHERE organisation_id = ?`, orgID)
+	if q.Deleted {
+		where.Write(` AND deleted_at IS NOT NULL`)
+	} else {
+		where.Write(` AND deleted_at IS NULL`)
+	}
+	if q.Status != nil {
+		where.Write(` AND status = ?`, *q.Status)
+	}
+	if q.Completed != nil {
+		where.Write(` AND is_completed = ?`, *q.Completed)
+	}
+	if q.WorkerID != nil {
+		where.Write(` AND worker_id = ?`, *q.WorkerID)
+	}
+	if q.CustomerID != nil {
+		where.Write(` AND customer_id = ?`, *q.CustomerID)
+	}
+	if q.InteriorType != "" {
+		where.Write(` AND EXISTS (SELECT 1 FROM measurements m WHERE m.project_id = projects.id AND m.interior_type = ?)`, q.InteriorType)
+	}
+	if q.Phone != "" {
+		where.Write(` AND phone LIKE ? ESCAPE '\'`, Contains(q.Phone))
+	}
+	if q.From != nil {
+		where.Write(` AND created_at >= ?`, *q.From)
+	}
+	if q.To != nil {
+		where.Write(` AND created_at < ?`, *q.To)
+	}
+	var counts store.ProjectCounts
+	count := s.dialect.Query(`SELECT COUNT(*), COUNT(CASE WHEN is_completed = ? THEN 1 END) FROM projects`, true).Append(where)
+	if err := s.db.QueryRow(count.SQL(), count.Args()...).Scan(&counts.Total, &counts.Completed); err != nil {
+		return nil, counts, err
+	}
+	column, ok := projectSortColumns[q.Sort]
+	if !ok {
+		column = projectSortColumns[store.SortCreatedAt]
+	}
+	dir := "ASC"
+	if q.Desc {
+		dir = "DESC"
+	}
+	list := s.dialect.Query(`SELECT `+projectListColumns+` FROM projects`).Append(where).
+		Write(` ORDER BY `+column+` `+dir+`, id `+dir).Page(q.Limit, q.Offset)
+	projects, err := s.queryProjects(list)
+	return projects, counts, err
+}
+
+func (s *Store) ListProjectsByID(ids []int, orgID int) ([]models.Project, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	q := s.dialect.Query(`SELECT `+projectListColumns+` FROM projects WHERE organisation_id = ? AND deleted_at IS NULL AND id IN (`, orgID)
+	for i, id := range ids {
+		if i > 0 {
+			q.Write(`, `)
+		}
+		q.Write(`?`, id)
+	}
+	return s.queryProjects(q.Write(`)`))
+}
+
+func (s *Store) GetWorkerProject(id, workerID int) (*models.Project, error) {
+	return s.getProject(s.dialect.Query(`SELECT `+projectColumns+` FROM projects WHERE id = ? AND worker_id = ? AND deleted_at IS NULL`, id, workerID))
+}
+
+func (s *Store) ListProjectsByWorker(workerID int, q store.WorkerProjectQuery) ([]models.Project, int, error) {
+	where := s.dialect.Query(` WHERE worker_id = ?`, workerID)
+	if q.Since != nil {
+		where.Write(` AND updated_at >= ?`, *q.Since)
+	} else {
+		where.Write(` AND deleted_at IS NULL`)
+	}
+	count := s.dialect.Query(`SELECT COUNT(*) FROM projects`).Append(where)
+	var total int
+	if err := s.db.QueryRow(count.SQL(), count.Args()...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	list := s.dialect.Query(`SELECT `+projectColumns+` FROM projects`).Append(where).
+		Write(` ORDER BY updated_at, id`).Page(q.Limit, q.Offset)
+	projects, err := s.queryProjects(list)
+	return projects, total, err
+}
+
+func (s *Store) ListProjectsAfter(afterID, limit int) ([]models.Project, error) {
+	return s.queryProjects(s.dialect.Query(`SELECT `+projectColumns+` FROM projects WHERE id > ? ORDER BY id`, afterID).Page(limit, 0))
+}
+
+func (s *Store) SetProjectStatus(t *models.StatusTransition) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := s.dialect.Query(`UPDATE projects SET status = ?, updated_at = ?`, t.ToStatus, Now)
+	if lifecycle.Completed(t.ToStatus) {
+		q.Write(`, is_completed = ?`, true)
+	}
+	// Only move the project if nobody else changed its status in the meantime
+	q.Write(` WHERE id = ? AND status = ? AND deleted_at IS NULL`, t.ProjectID, t.FromStatus)
+	err = affected(tx.Exec(q.SQL(), q.Args()...))
+	if err == store.ErrNotFound {
+		return store.ErrConflict
+	} else if err != nil {
+		return err
+	}
+	q = s.dialect.InsertReturning("project_status_transitions",
+		[]string{"project_id", "from_status", "to_status", "actor_role", "actor_id", "note", "created_at"}, []string{"id", "created_at"},
+		t.ProjectID, t.FromStatus, t.ToStatus, t.ActorRole, t.ActorID, t.Note, Now)
+	if err := tx.QueryRow(q.SQL(), q.Args()...).Scan(&t.ID, &t.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) ListStatusTransitions(projectID int) ([]models.StatusTransition, error) {
+	q := s.dialect.Query(`SELECT id, project_id, from_status, to_status, actor_role, actor_id, note, created_at FROM project_status_transitions WHERE project_id = ? ORDER BY id`, projectID)
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	transitions := []models.StatusTransition{}
+	for rows.Next() {
+		var t models.StatusTransition
+		if err := rows.Scan(&t.ID, &t.ProjectID, &t.FromStatus, &t.ToStatus, &t.ActorRole, &t.ActorID, &t.Note, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
+
+// DeleteProject also bumps updated_at so delta syncs pick up the deletion
+func (s *Store) DeleteProject(id, orgID int) error {
+	q := s.dialect.Query(`UPDATE projects SET deleted_at = ?, updated_at = ? WHERE id = ? AND organisation_id = ? AND deleted_at IS NULL`, Now, Now, id, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) RestoreProject(id, orgID int) error {
+	q := s.dialect.Query(`UPDATE projects SET deleted_at = NULL, updated_at = ? WHERE id = ? AND organisation_id = ? AND deleted_at IS NOT NULL`, Now, id, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) ReassignProjects(fromWorkerID, toWorkerID, orgID int) (int, error) {
+	q := s.dialect.Query(`UPDATE projects SET worker_id = ?, updated_at = ? WHERE worker_id = ? AND organisation_id = ?`, toWorkerID, Now, fromWorkerID, orgID)
+	return rowsAffected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) PurgeProjects(deletedBefore time.Time) (int, error) {
+	q := s.dialect.Query(`DELETE FROM projects WHERE deleted_at < ?`, deletedBefore)
+	return rowsAffected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) queryProjects(q *Query) ([]models.Project, error) {
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var projects []models.Project
+	for rows.Next() {
+		var p models.Project
+		if err := scanProject(rows, &p); err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
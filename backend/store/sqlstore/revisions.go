@@ -0,0 +1,68 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Vanaraj10/interior-backend/models"
+)
+
+const revisionColumns = `id, project_id, revision, client_name, phone, address, html, raw_data, worker_id, created_at`
+
+func scanRevision(row interface{ Scan(...interface{}) error }, r *models.ProjectRevision) error {
+	return row.Scan(&r.ID, &r.ProjectID, &r.Revision, &r.ClientName, &r.Phone, &r.Address, &r.HTML, &r.RawData, &r.WorkerID, &r.CreatedAt)
+}
+
+func (s *Store) CreateRevision(r *models.ProjectRevision) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := s.insertRevision(tx, r); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) insertRevision(tx *sql.Tx, r *models.ProjectRevision) error {
+	// Locking the project row keeps concurrent saves of it from taking the same number
+	q := s.dialect.Query(`UPDATE projects SET updated_at = updated_at WHERE id = ?`, r.ProjectID)
+	if _, err := tx.Exec(q.SQL(), q.Args()...); err != nil {
+		return err
+	}
+	q = s.dialect.Query(`SELECT COALESCE(MAX(revision), 0) + 1 FROM project_revisions WHERE project_id = ?`, r.ProjectID)
+	if err := tx.QueryRow(q.SQL(), q.Args()...).Scan(&r.Revision); err != nil {
+		return err
+	}
+	q = s.dialect.InsertReturning("project_revisions",
+		[]string{"project_id", "revision", "client_name", "phone", "address", "html", "raw_data", "worker_id", "created_at"}, []string{"id", "created_at"},
+		r.ProjectID, r.Revision, r.ClientName, r.Phone, r.Address, r.HTML, r.RawData, r.WorkerID, Now)
+	return tx.QueryRow(q.SQL(), q.Args()...).Scan(&r.ID, &r.CreatedAt)
+}
+
+func (s *Store) ListRevisions(projectID int) ([]models.ProjectRevision, error) {
+	q := s.dialect.Query(`SELECT `+revisionColumns+` FROM project_revisions WHERE project_id = ? ORDER BY revision`, projectID)
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	revisions := []models.ProjectRevision{}
+	for rows.Next() {
+		var r models.ProjectRevision
+		if err := scanRevision(rows, &r); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+	return revisions, rows.Err()
+}
+
+func (s *Store) GetRevision(projectID, revision int) (*models.ProjectRevision, error) {
+	var r models.ProjectRevision
+	q := s.dialect.Query(`SELECT `+revisionColumns+` FROM project_revisions WHERE project_id = ? AND revision = ?`, projectID, revision)
+	if err := scanRevision(s.db.QueryRow(q.SQL(), q.Args()...), &r); err != nil {
+		return nil, notFound(err)
+	}
+	return &r, nil
+}
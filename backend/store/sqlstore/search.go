@@ -0,0 +1,67 @@
+package sqlstore
+
+import (
+	"fmt"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) ReplaceProjectTerms(projectID, orgID int, terms []models.SearchTerm) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := s.dialect.Query(`DELETE FROM search_terms WHERE project_id = ?`, projectID)
+	if _, err := tx.Exec(q.SQL(), q.Args()...); err != nil {
+		return err
+	}
+	for _, t := range terms {
+		q := s.dialect.Query(`INSERT INTO search_terms (project_id, organisation_id, field, term, weight) VALUES (?, ?, ?, ?, ?)`,
+			projectID, orgID, t.Field, t.Term, t.Weight)
+		if _, err := tx.Exec(q.SQL(), q.Args()...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) SearchProjects(orgID int, terms []string, limit int) ([]store.SearchHit, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	// One row per project and query term with the best match, then projects
+	// that matched every term
+	q := s.dialect.Query(`SELECT m.project_id, SUM(m.score) FROM (`)
+	for i, term := range terms {
+		if i > 0 {
+			q.Write(`
+	UNION ALL`)
+		}
+		q.Write(`
+	SELECT project_id, MAX(CASE WHEN term = ? THEN 2 * weight ELSE weight END) AS score
+	FROM search_terms WHERE organisation_id = ? AND term LIKE ? ESCAPE '\'
+	GROUP BY project_id`, term, orgID, StartsWith(term))
+	}
+	q.Write(`
+) m
+JOIN projects p ON p.id = m.project_id AND p.deleted_at IS NULL
+GROUP BY m.project_id
+HAVING COUNT(*) = `+fmt.Sprint(len(terms))+`
+ORDER BY SUM(m.score) DESC, m.project_id DESC`).Page(limit, 0)
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hits []store.SearchHit
+	for rows.Next() {
+		var h store.SearchHit
+		if err := rows.Scan(&h.ProjectID, &h.Score); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
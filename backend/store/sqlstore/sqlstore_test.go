@@ -0,0 +1,763 @@
+package sqlstore_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/config"
+	"github.com/Vanaraj10/interior-backend/lifecycle"
+	"github.com/Vanaraj10/interior-backend/migrations"
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+	"github.com/Vanaraj10/interior-backend/store/sqlstore"
+)
+
+// openStore returns the repositories on the PostgreSQL database named by
+// TEST_POSTGRES_DSN or, when that is not set, the SQL Server database named
+// by TEST_MSSQL_DSN, with every migration applied. It skips the test when
+// neither is set. The tests only add rows of organisations they create, apart
+// from the purges, but the database should still be a scratch one.
+func openStore(t *testing.T) *store.Store {
+	t.Helper()
+	driver, dialect, dsn := "postgres", sqlstore.Postgres, os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		driver, dialect, dsn = "mssql", sqlstore.MSSQL, os.Getenv("TEST_MSSQL_DSN")
+	}
+	if dsn == "" {
+		t.Skip("neither TEST_POSTGRES_DSN nor TEST_MSSQL_DSN is set")
+	}
+	db, err := config.OpenDB(driver, config.DatabaseConfig{DSN: dsn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := migrations.Up(db, driver); err != nil {
+		t.Fatalf("migrations: %v", err)
+	}
+	return sqlstore.New(db, dialect)
+}
+
+var uniqueCount atomic.Int64
+
+// unique returns a name no earlier run has used, since usernames are unique
+// across organisations
+func unique(prefix string) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, time.Now().UnixNano(), uniqueCount.Add(1))
+}
+
+func newOrganisation(t *testing.T, s *store.Store) (*models.Organisation, *models.Admin) {
+	t.Helper()
+	o := &models.Organisation{Name: unique("org")}
+	owner := &models.Admin{Username: unique("owner"), PasswordHash: "hash", Role: "owner"}
+	if err := s.Admins.CreateOrganisation(o, owner); err != nil {
+		t.Fatalf("CreateOrganisation: %v", err)
+	}
+	return o, owner
+}
+
+func newWorker(t *testing.T, s *store.Store, orgID int) *models.Worker {
+	t.Helper()
+	w := &models.Worker{Username: unique("worker"), PasswordHash: "hash", OrganisationID: orgID, Name: "Ravi", Phone: "9876543210", IsActive: true}
+	if err := s.Workers.CreateWorker(w); err != nil {
+		t.Fatalf("CreateWorker: %v", err)
+	}
+	return w
+}
+
+func newProject(t *testing.T, s *store.Store, w *models.Worker, client, phone string) *models.Project {
+	t.Helper()
+	p := &models.Project{ClientName: client, Phone: phone, Address: "12 Main Road", HTML: "<p>quote</p>", RawData: `{"rooms":[]}`, WorkerID: w.ID, OrganisationID: w.OrganisationID}
+	if err := s.Projects.CreateProject(p); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	return p
+}
+
+func wantErr(t *testing.T, what string, got, want error) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("%s: got error %v, want %v", what, got, want)
+	}
+}
+
+func TestAdmins(t *testing.T) {
+	s := openStore(t)
+	o, owner := newOrganisation(t, s)
+	if o.ID == 0 || owner.ID == 0 || owner.OrganisationID != o.ID {
+		t.Fatalf("CreateOrganisation left ids unset: %+v %+v", o, owner)
+	}
+	wantErr(t, "CreateOrganisation with a taken username", s.Admins.CreateOrganisation(&models.Organisation{Name: "x"}, &models.Admin{Username: owner.Username, PasswordHash: "hash", Role: "owner"}), store.ErrConflict)
+	// The organisations above are admins already, so first-run setup is over
+	wantErr(t, "CreateFirstOrganisation", s.Admins.CreateFirstOrganisation(&models.Organisation{Name: "x"}, &models.Admin{Username: unique("first"), PasswordHash: "hash", Role: "owner"}), store.ErrConflict)
+
+	if err := s.Admins.UpdateOrganisation(o.ID, "Renamed"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Admins.GetOrganisation(o.ID)
+	if err != nil || got.Name != "Renamed" {
+		t.Fatalf("GetOrganisation = %+v, %v", got, err)
+	}
+
+	manager := &models.Admin{Username: unique("manager"), PasswordHash: "hash", OrganisationID: o.ID, Role: "manager"}
+	if err := s.Admins.CreateAdmin(manager); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "CreateAdmin with a taken username", s.Admins.CreateAdmin(&models.Admin{Username: manager.Username, PasswordHash: "hash", OrganisationID: o.ID, Role: "manager"}), store.ErrConflict)
+	admins, err := s.Admins.ListAdmins(o.ID)
+	if err != nil || len(admins) != 2 || admins[0].ID != owner.ID || admins[1].ID != manager.ID {
+		t.Fatalf("ListAdmins = %+v, %v", admins, err)
+	}
+	if err := s.Admins.UpdateAdminPassword(manager.ID, "newhash"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Admins.UpdateAdminRole(manager.ID, o.ID, "accountant"); err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.Admins.GetAdminByUsername(manager.Username)
+	if err != nil || a.PasswordHash != "newhash" || a.Role != "accountant" {
+		t.Fatalf("GetAdminByUsername = %+v, %v", a, err)
+	}
+	wantErr(t, "UpdateAdminRole in another organisation", s.Admins.UpdateAdminRole(manager.ID, o.ID+1, "owner"), store.ErrNotFound)
+	if err := s.Admins.DeleteAdmin(manager.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Admins.GetAdmin(manager.ID)
+	wantErr(t, "GetAdmin after DeleteAdmin", err, store.ErrNotFound)
+	if n, err := s.Admins.CountAdmins(); err != nil || n < 1 {
+		t.Fatalf("CountAdmins = %d, %v", n, err)
+	}
+
+	inv := &models.Invite{CodeHash: unique("code"), Note: "for Acme", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.Admins.CreateInvite(inv); err != nil {
+		t.Fatal(err)
+	}
+	org := &models.Organisation{Name: unique("invited")}
+	invited := &models.Admin{Username: unique("invited"), PasswordHash: "hash", Role: "owner"}
+	if err := s.Admins.RedeemInvite(inv.CodeHash, org, invited); err != nil {
+		t.Fatal(err)
+	}
+	if org.ID == 0 || invited.OrganisationID != org.ID {
+		t.Fatalf("RedeemInvite left ids unset: %+v %+v", org, invited)
+	}
+	wantErr(t, "RedeemInvite twice", s.Admins.RedeemInvite(inv.CodeHash, &models.Organisation{Name: "x"}, &models.Admin{Username: unique("again"), PasswordHash: "hash", Role: "owner"}), store.ErrNotFound)
+
+	expired := &models.Invite{CodeHash: unique("code"), ExpiresAt: time.Now().Add(-time.Hour)}
+	if err := s.Admins.CreateInvite(expired); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "RedeemInvite expired", s.Admins.RedeemInvite(expired.CodeHash, &models.Organisation{Name: "x"}, &models.Admin{Username: unique("late"), PasswordHash: "hash", Role: "owner"}), store.ErrNotFound)
+}
+
+func TestWorkers(t *testing.T) {
+	s := openStore(t)
+	o, _ := newOrganisation(t, s)
+	w := newWorker(t, s, o.ID)
+	other := newWorker(t, s, o.ID)
+
+	got, err := s.Workers.GetWorkerByUsername(w.Username)
+	if err != nil || got.ID != w.ID || !got.IsActive {
+		t.Fatalf("GetWorkerByUsername = %+v, %v", got, err)
+	}
+	_, err = s.Workers.GetWorker(w.ID, o.ID+1)
+	wantErr(t, "GetWorker in another organisation", err, store.ErrNotFound)
+	if exists, err := s.Workers.WorkerUsernameExists(w.Username); err != nil || !exists {
+		t.Fatalf("WorkerUsernameExists = %v, %v", exists, err)
+	}
+
+	name, inactive := "Ravi Kumar", false
+	if err := s.Workers.UpdateWorker(w.ID, o.ID, store.WorkerUpdate{Name: &name, IsActive: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "UpdateWorker to a taken username", s.Workers.UpdateWorker(w.ID, o.ID, store.WorkerUpdate{Username: &other.Username}), store.ErrConflict)
+	if err := s.Workers.UpdateWorkerPassword(w.ID, o.ID, "newhash"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.Workers.GetWorker(w.ID, o.ID)
+	if err != nil || got.Name != name || got.IsActive || got.PasswordHash != "newhash" {
+		t.Fatalf("GetWorker = %+v, %v", got, err)
+	}
+
+	if err := s.Workers.DeleteWorker(other.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	workers, err := s.Workers.ListWorkers(o.ID, store.WorkerFilter{})
+	if err != nil || len(workers) != 1 || workers[0].ID != w.ID {
+		t.Fatalf("ListWorkers = %+v, %v", workers, err)
+	}
+	deleted, err := s.Workers.ListWorkers(o.ID, store.WorkerFilter{Deleted: true})
+	if err != nil || len(deleted) != 1 || deleted[0].ID != other.ID || deleted[0].DeletedAt == nil {
+		t.Fatalf("ListWorkers deleted = %+v, %v", deleted, err)
+	}
+	if err := s.Workers.RestoreWorker(other.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "RestoreWorker twice", s.Workers.RestoreWorker(other.ID, o.ID), store.ErrNotFound)
+
+	if err := s.Workers.DeleteWorker(other.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.Workers.PurgeWorkers(time.Now().Add(time.Hour)); err != nil || n < 1 {
+		t.Fatalf("PurgeWorkers = %d, %v", n, err)
+	}
+	if exists, err := s.Workers.WorkerUsernameExists(other.Username); err != nil || exists {
+		t.Fatalf("WorkerUsernameExists after purge = %v, %v", exists, err)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	s := openStore(t)
+	o, owner := newOrganisation(t, s)
+	w := newWorker(t, s, o.ID)
+	p := newProject(t, s, w, "Anitha", "98765 43210")
+	if p.ID == 0 || p.Status != lifecycle.Measured || p.IsCompleted || p.CreatedAt.IsZero() {
+		t.Fatalf("CreateProject = %+v", p)
+	}
+	second := newProject(t, s, w, "Bala", "044 2345 6789")
+
+	p.ClientName = "Anitha R"
+	if err := s.Projects.UpdateProject(p); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Projects.GetProject(p.ID, o.ID)
+	if err != nil || got.ClientName != "Anitha R" || got.HTML != p.HTML {
+		t.Fatalf("GetProject = %+v, %v", got, err)
+	}
+	_, err = s.Projects.GetProject(p.ID, o.ID+1)
+	wantErr(t, "GetProject in another organisation", err, store.ErrNotFound)
+	if _, err := s.Projects.GetWorkerProject(p.ID, w.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Projects.SetProjectStatus(&models.StatusTransition{ProjectID: second.ID, FromStatus: lifecycle.Measured, ToStatus: lifecycle.Installed, ActorRole: lifecycle.RoleWorker, ActorID: w.ID}); err != nil {
+		t.Fatal(err)
+	}
+	projects, counts, err := s.Projects.ListProjectsByOrganisation(o.ID, store.ProjectQuery{Sort: store.SortClientName, Limit: 1})
+	if err != nil || counts.Total != 2 || counts.Completed != 1 || len(projects) != 1 || projects[0].ID != p.ID || projects[0].HTML != "" {
+		t.Fatalf("ListProjectsByOrganisation = %+v, %+v, %v", projects, counts, err)
+	}
+	projects, _, err = s.Projects.ListProjectsByOrganisation(o.ID, store.ProjectQuery{Sort: store.SortClientName, Limit: 1, Offset: 1})
+	if err != nil || len(projects) != 1 || projects[0].ID != second.ID {
+		t.Fatalf("ListProjectsByOrganisation second page = %+v, %v", projects, err)
+	}
+	projects, counts, err = s.Projects.ListProjectsByOrganisation(o.ID, store.ProjectQuery{Phone: "2345", WorkerID: &w.ID, Limit: 10})
+	if err != nil || counts.Total != 1 || projects[0].ID != second.ID {
+		t.Fatalf("ListProjectsByOrganisation by phone = %+v, %+v, %v", projects, counts, err)
+	}
+	// LIKE wildcards in the filter are matched literally
+	if _, counts, err := s.Projects.ListProjectsByOrganisation(o.ID, store.ProjectQuery{Phone: "%", Limit: 10}); err != nil || counts.Total != 0 {
+		t.Fatalf("ListProjectsByOrganisation by %% = %+v, %v", counts, err)
+	}
+
+	quoted := &models.StatusTransition{ProjectID: p.ID, FromStatus: lifecycle.Measured, ToStatus: lifecycle.Quoted, ActorRole: lifecycle.RoleAdmin, ActorID: owner.ID, Note: "sent"}
+	if err := s.Projects.SetProjectStatus(quoted); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "SetProjectStatus from a stale status", s.Projects.SetProjectStatus(&models.StatusTransition{ProjectID: p.ID, FromStatus: lifecycle.Measured, ToStatus: lifecycle.Quoted, ActorRole: lifecycle.RoleAdmin, ActorID: owner.ID}), store.ErrConflict)
+	closed := &models.StatusTransition{ProjectID: p.ID, FromStatus: lifecycle.Quoted, ToStatus: lifecycle.Closed, ActorRole: lifecycle.RoleAdmin, ActorID: owner.ID}
+	if err := s.Projects.SetProjectStatus(closed); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.Projects.GetProject(p.ID, o.ID)
+	if err != nil || got.Status != lifecycle.Closed || !got.IsCompleted {
+		t.Fatalf("GetProject after closing = %+v, %v", got, err)
+	}
+	status := lifecycle.Closed
+	if _, counts, err := s.Projects.ListProjectsByOrganisation(o.ID, store.ProjectQuery{Status: &status, Limit: 10}); err != nil || counts.Total != 1 {
+		t.Fatalf("ListProjectsByOrganisation by status = %+v, %v", counts, err)
+	}
+	transitions, err := s.Projects.ListStatusTransitions(p.ID)
+	if err != nil || len(transitions) != 2 || transitions[0].ID != quoted.ID || transitions[0].Note != "sent" || transitions[1].ToStatus != lifecycle.Closed {
+		t.Fatalf("ListStatusTransitions = %+v, %v", transitions, err)
+	}
+
+	since := time.Now().Add(-time.Hour)
+	if err := s.Projects.DeleteProject(second.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "DeleteProject twice", s.Projects.DeleteProject(second.ID, o.ID), store.ErrNotFound)
+	projects, total, err := s.Projects.ListProjectsByWorker(w.ID, store.WorkerProjectQuery{Limit: 10})
+	if err != nil || total != 1 || projects[0].ID != p.ID {
+		t.Fatalf("ListProjectsByWorker = %+v, %d, %v", projects, total, err)
+	}
+	projects, total, err = s.Projects.ListProjectsByWorker(w.ID, store.WorkerProjectQuery{Since: &since, Limit: 10})
+	if err != nil || total != 2 || projects[1].ID != second.ID || projects[1].DeletedAt == nil {
+		t.Fatalf("ListProjectsByWorker since = %+v, %d, %v", projects, total, err)
+	}
+	projects, counts, err = s.Projects.ListProjectsByOrganisation(o.ID, store.ProjectQuery{Deleted: true, Limit: 10})
+	if err != nil || counts.Total != 1 || projects[0].ID != second.ID {
+		t.Fatalf("ListProjectsByOrganisation deleted = %+v, %+v, %v", projects, counts, err)
+	}
+	if err := s.Projects.RestoreProject(second.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := s.Projects.ListProjectsAfter(p.ID-1, 2)
+	if err != nil || len(after) != 2 || after[0].ID != p.ID || after[1].ID != second.ID {
+		t.Fatalf("ListProjectsAfter = %+v, %v", after, err)
+	}
+
+	next := newWorker(t, s, o.ID)
+	if n, err := s.Projects.ReassignProjects(w.ID, next.ID, o.ID); err != nil || n != 2 {
+		t.Fatalf("ReassignProjects = %d, %v", n, err)
+	}
+	if _, err := s.Projects.GetWorkerProject(p.ID, next.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Projects.DeleteProject(second.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.Projects.PurgeProjects(time.Now().Add(time.Hour)); err != nil || n < 1 {
+		t.Fatalf("PurgeProjects = %d, %v", n, err)
+	}
+	if after, err := s.Projects.ListProjectsAfter(second.ID-1, 1); err != nil || (len(after) == 1 && after[0].ID == second.ID) {
+		t.Fatalf("ListProjectsAfter a purged project = %+v, %v", after, err)
+	}
+}
+
+func TestMeasurements(t *testing.T) {
+	s := openStore(t)
+	o, _ := newOrganisation(t, s)
+	w := newWorker(t, s, o.ID)
+	p := newProject(t, s, w, "Anitha", "9876543210")
+
+	rooms := []models.Room{{RoomKey: "r1", Name: "Hall", Type: "living"}}
+	measurements := []models.Measurement{
+		{RoomKey: "r1", Position: 0, InteriorType: "curtain", RoomLabel: "Hall", Width: 120, Height: 84, Total: 4500.5},
+		{Position: 1, InteriorType: "blind", Width: 36, Height: 48, Total: 1200},
+	}
+	if err := s.Measurements.ReplaceProjectMeasurements(p.ID, rooms, measurements); err != nil {
+		t.Fatal(err)
+	}
+	// Replacing twice must not leave the first set behind
+	if err := s.Measurements.ReplaceProjectMeasurements(p.ID, rooms, measurements); err != nil {
+		t.Fatal(err)
+	}
+	gotRooms, got, err := s.Measurements.ListProjectMeasurements(p.ID)
+	if err != nil || len(gotRooms) != 1 || len(got) != 2 {
+		t.Fatalf("ListProjectMeasurements = %+v, %+v, %v", gotRooms, got, err)
+	}
+	if got[0].RoomID == nil || *got[0].RoomID != gotRooms[0].ID || got[0].RoomKey != "r1" || got[0].Total != 4500.5 {
+		t.Fatalf("first measurement = %+v", got[0])
+	}
+	if got[1].RoomID != nil || got[1].InteriorType != "blind" {
+		t.Fatalf("second measurement = %+v", got[1])
+	}
+
+	newProject(t, s, w, "Bala", "9876500000")
+	if projects, counts, err := s.Projects.ListProjectsByOrganisation(o.ID, store.ProjectQuery{InteriorType: "blind", Limit: 10}); err != nil || counts.Total != 1 || projects[0].ID != p.ID {
+		t.Fatalf("ListProjectsByOrganisation by interior type = %+v, %+v, %v", projects, counts, err)
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	s := openStore(t)
+	o, _ := newOrganisation(t, s)
+	w := newWorker(t, s, o.ID)
+	p := newProject(t, s, w, "Anitha", "9876543210")
+
+	for i, client := range []string{"Anitha", "Anitha R"} {
+		r := &models.ProjectRevision{ProjectID: p.ID, ClientName: client, Phone: p.Phone, Address: p.Address, HTML: p.HTML, RawData: p.RawData, WorkerID: w.ID}
+		if err := s.Revisions.CreateRevision(r); err != nil {
+			t.Fatal(err)
+		}
+		if r.ID == 0 || r.Revision != i+1 || r.CreatedAt.IsZero() {
+			t.Fatalf("CreateRevision = %+v", r)
+		}
+	}
+	revisions, err := s.Revisions.ListRevisions(p.ID)
+	if err != nil || len(revisions) != 2 || revisions[1].Revision != 2 {
+		t.Fatalf("ListRevisions = %+v, %v", revisions, err)
+	}
+	r, err := s.Revisions.GetRevision(p.ID, 2)
+	if err != nil || r.ClientName != "Anitha R" {
+		t.Fatalf("GetRevision = %+v, %v", r, err)
+	}
+	_, err = s.Revisions.GetRevision(p.ID, 3)
+	wantErr(t, "GetRevision of a missing revision", err, store.ErrNotFound)
+
+	// SaveProject updates the project and appends the next revision together
+	p.ClientName = "Anitha Ravi"
+	r = &models.ProjectRevision{ClientName: p.ClientName, Phone: p.Phone, WorkerID: w.ID}
+	if err := s.Projects.SaveProject(p, r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ProjectID != p.ID || r.Revision != 3 {
+		t.Fatalf("SaveProject revision = %+v", r)
+	}
+	other := newWorker(t, s, o.ID)
+	stolen := &models.Project{ID: p.ID, ClientName: "Mallory", WorkerID: other.ID, OrganisationID: o.ID}
+	err = s.Projects.SaveProject(stolen, &models.ProjectRevision{WorkerID: other.ID})
+	wantErr(t, "SaveProject of another worker's project", err, store.ErrNotFound)
+	if revisions, _ := s.Revisions.ListRevisions(p.ID); len(revisions) != 3 {
+		t.Fatalf("failed SaveProject left %d revisions, want 3", len(revisions))
+	}
+
+	created := &models.Project{ClientName: "Bala", Phone: "9000000000", WorkerID: w.ID, OrganisationID: o.ID}
+	r = &models.ProjectRevision{ClientName: created.ClientName, Phone: created.Phone, WorkerID: w.ID}
+	if err := s.Projects.SaveProject(created, r); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == 0 || r.ProjectID != created.ID || r.Revision != 1 {
+		t.Fatalf("SaveProject of a new project = %+v, %+v", created, r)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	s := openStore(t)
+	o, _ := newOrganisation(t, s)
+
+	_, err := s.Templates.GetQuotationTemplate(o.ID)
+	wantErr(t, "GetQuotationTemplate before saving", err, store.ErrNotFound)
+	tmpl := &models.QuotationTemplate{OrganisationID: o.ID, CompanyName: "Acme", Notes: []string{"50% advance", "GST extra"}}
+	if err := s.Templates.SaveQuotationTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.UpdatedAt.IsZero() {
+		t.Fatal("SaveQuotationTemplate left UpdatedAt unset")
+	}
+	got, err := s.Templates.GetQuotationTemplate(o.ID)
+	if err != nil || got.CompanyName != "Acme" || len(got.Notes) != 2 || got.Notes[1] != "GST extra" {
+		t.Fatalf("GetQuotationTemplate = %+v, %v", got, err)
+	}
+
+	tmpl = &models.QuotationTemplate{OrganisationID: o.ID, CompanyName: "Acme Interiors", StitchingTemplate: "{{.Total}}"}
+	if err := s.Templates.SaveQuotationTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.Templates.GetQuotationTemplate(o.ID)
+	if err != nil || got.CompanyName != "Acme Interiors" || got.Notes != nil || got.StitchingTemplate != "{{.Total}}" {
+		t.Fatalf("GetQuotationTemplate after replacing = %+v, %v", got, err)
+	}
+	if err := s.Templates.DeleteQuotationTemplate(o.ID); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "DeleteQuotationTemplate twice", s.Templates.DeleteQuotationTemplate(o.ID), store.ErrNotFound)
+}
+
+func TestAudit(t *testing.T) {
+	s := openStore(t)
+	o, owner := newOrganisation(t, s)
+
+	entityID := 7
+	for _, method := range []string{"POST", "PUT", "DELETE"} {
+		e := &models.AuditEntry{OrganisationID: o.ID, ActorRole: "admin", ActorID: owner.ID, Method: method, Route: "/api/admin/brands/:id", Path: "/api/admin/brands/7",
+			EntityType: "brand", EntityID: &entityID, Status: 200, After: json.RawMessage(`{"name":"Acme"}`), ClientIP: "127.0.0.1"}
+		if err := s.Audit.RecordAudit(e); err != nil {
+			t.Fatal(err)
+		}
+		if e.ID == 0 {
+			t.Fatal("RecordAudit left ID unset")
+		}
+	}
+	if err := s.Audit.RecordAudit(&models.AuditEntry{OrganisationID: o.ID, ActorRole: "worker", ActorID: 1, Method: "POST", Route: "/api/projects", Path: "/api/projects", EntityType: "project", Status: 201}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, total, err := s.Audit.ListAudit(o.ID, store.AuditQuery{EntityType: "brand", EntityID: &entityID, Limit: 2})
+	if err != nil || total != 3 || len(entries) != 2 || entries[0].Method != "DELETE" || string(entries[0].After) != `{"name":"Acme"}` {
+		t.Fatalf("ListAudit = %+v, %d, %v", entries, total, err)
+	}
+	entries, _, err = s.Audit.ListAudit(o.ID, store.AuditQuery{EntityType: "brand", Limit: 2, Offset: 2})
+	if err != nil || len(entries) != 1 || entries[0].Method != "POST" || entries[0].Before != nil {
+		t.Fatalf("ListAudit second page = %+v, %v", entries, err)
+	}
+	from := time.Now().Add(-time.Hour)
+	if _, total, err := s.Audit.ListAudit(o.ID, store.AuditQuery{ActorRole: "worker", From: &from, Limit: 10}); err != nil || total != 1 {
+		t.Fatalf("ListAudit by actor = %d, %v", total, err)
+	}
+}
+
+func TestLogins(t *testing.T) {
+	s := openStore(t)
+	o, owner := newOrganisation(t, s)
+
+	for _, reason := range []string{"bad_password", "bad_password"} {
+		f := &models.FailedLogin{OrganisationID: &o.ID, Role: "admin", Username: owner.Username, UserID: &owner.ID, Reason: reason, ClientIP: "127.0.0.1"}
+		if err := s.Logins.RecordFailedLogin(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Unknown usernames belong to no organisation and are not listed
+	if err := s.Logins.RecordFailedLogin(&models.FailedLogin{Role: "admin", Username: unique("nobody"), Reason: "unknown_user"}); err != nil {
+		t.Fatal(err)
+	}
+	logins, total, err := s.Logins.ListFailedLogins(o.ID, store.FailedLoginQuery{Role: "admin", Username: owner.Username, Limit: 1})
+	if err != nil || total != 2 || len(logins) != 1 || logins[0].UserID == nil || *logins[0].UserID != owner.ID {
+		t.Fatalf("ListFailedLogins = %+v, %d, %v", logins, total, err)
+	}
+	if _, total, err := s.Logins.ListFailedLogins(o.ID, store.FailedLoginQuery{Role: "worker", Limit: 10}); err != nil || total != 0 {
+		t.Fatalf("ListFailedLogins of workers = %d, %v", total, err)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	s := openStore(t)
+	o, owner := newOrganisation(t, s)
+
+	session := unique("session")
+	first := &models.RefreshToken{TokenHash: unique("token"), SessionID: session, Role: "admin", UserID: owner.ID, OrganisationID: o.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.Tokens.CreateRefreshToken(first); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Tokens.GetRefreshToken(first.TokenHash)
+	if err != nil || got.ID != first.ID || got.RevokedAt != nil {
+		t.Fatalf("GetRefreshToken = %+v, %v", got, err)
+	}
+	if active, err := s.Tokens.SessionActive(session); err != nil || !active {
+		t.Fatalf("SessionActive = %v, %v", active, err)
+	}
+
+	next := &models.RefreshToken{TokenHash: unique("token"), SessionID: session, Role: "admin", UserID: owner.ID, OrganisationID: o.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.Tokens.RotateRefreshToken(first.ID, next); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "RotateRefreshToken twice", s.Tokens.RotateRefreshToken(first.ID, &models.RefreshToken{TokenHash: unique("token"), SessionID: session, Role: "admin", UserID: owner.ID, OrganisationID: o.ID, ExpiresAt: time.Now().Add(time.Hour)}), store.ErrConflict)
+	if got, err := s.Tokens.GetRefreshToken(first.TokenHash); err != nil || got.RevokedAt == nil {
+		t.Fatalf("GetRefreshToken of a rotated token = %+v, %v", got, err)
+	}
+
+	if err := s.Tokens.RevokeSession(session); err != nil {
+		t.Fatal(err)
+	}
+	if active, err := s.Tokens.SessionActive(session); err != nil || active {
+		t.Fatalf("SessionActive after RevokeSession = %v, %v", active, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.Tokens.CreateRefreshToken(&models.RefreshToken{TokenHash: unique("token"), SessionID: unique("session"), Role: "admin", UserID: owner.ID, OrganisationID: o.ID, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := s.Tokens.RevokeUserSessions("admin", owner.ID); err != nil || n != 2 {
+		t.Fatalf("RevokeUserSessions = %d, %v", n, err)
+	}
+
+	var sessions []string
+	for i := 0; i < 3; i++ {
+		sessions = append(sessions, unique("session"))
+		if err := s.Tokens.CreateRefreshToken(&models.RefreshToken{TokenHash: unique("token"), SessionID: sessions[i], Role: "admin", UserID: owner.ID, OrganisationID: o.ID, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := s.Tokens.RevokeOtherSessions("admin", owner.ID, sessions[0]); err != nil || n != 2 {
+		t.Fatalf("RevokeOtherSessions = %d, %v", n, err)
+	}
+	if active, err := s.Tokens.SessionActive(sessions[0]); err != nil || !active {
+		t.Fatalf("SessionActive of the kept session = %v, %v", active, err)
+	}
+	if active, err := s.Tokens.SessionActive(sessions[1]); err != nil || active {
+		t.Fatalf("SessionActive of another session = %v, %v", active, err)
+	}
+
+	expired := &models.RefreshToken{TokenHash: unique("token"), SessionID: unique("session"), Role: "admin", UserID: owner.ID, OrganisationID: o.ID, ExpiresAt: time.Now().Add(-time.Hour)}
+	if err := s.Tokens.CreateRefreshToken(expired); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.Tokens.PurgeRefreshTokens(time.Now()); err != nil || n < 1 {
+		t.Fatalf("PurgeRefreshTokens = %d, %v", n, err)
+	}
+	_, err = s.Tokens.GetRefreshToken(expired.TokenHash)
+	wantErr(t, "GetRefreshToken after purge", err, store.ErrNotFound)
+}
+
+func TestSearch(t *testing.T) {
+	s := openStore(t)
+	o, _ := newOrganisation(t, s)
+	w := newWorker(t, s, o.ID)
+	anitha := newProject(t, s, w, "Anitha", "9876543210")
+	anand := newProject(t, s, w, "Anand", "9123456780")
+
+	if err := s.Search.ReplaceProjectTerms(anitha.ID, o.ID, []models.SearchTerm{{Field: "client", Term: "anitha", Weight: 3}, {Field: "address", Term: "adyar", Weight: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Search.ReplaceProjectTerms(anand.ID, o.ID, []models.SearchTerm{{Field: "client", Term: "anand", Weight: 3}, {Field: "address", Term: "an_nagar", Weight: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	hits, err := s.Search.SearchProjects(o.ID, []string{"an"}, 10)
+	if err != nil || len(hits) != 2 {
+		t.Fatalf("SearchProjects an = %+v, %v", hits, err)
+	}
+	hits, err = s.Search.SearchProjects(o.ID, []string{"anand"}, 10)
+	if err != nil || len(hits) != 1 || hits[0].ProjectID != anand.ID || hits[0].Score != 6 {
+		t.Fatalf("SearchProjects anand = %+v, %v", hits, err)
+	}
+	hits, err = s.Search.SearchProjects(o.ID, []string{"ani", "ady"}, 10)
+	if err != nil || len(hits) != 1 || hits[0].ProjectID != anitha.ID || hits[0].Score != 4 {
+		t.Fatalf("SearchProjects ani ady = %+v, %v", hits, err)
+	}
+	// LIKE wildcards in a term are matched literally
+	hits, err = s.Search.SearchProjects(o.ID, []string{"an_"}, 10)
+	if err != nil || len(hits) != 1 || hits[0].ProjectID != anand.ID {
+		t.Fatalf("SearchProjects an_ = %+v, %v", hits, err)
+	}
+
+	if err := s.Projects.DeleteProject(anand.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	if hits, err := s.Search.SearchProjects(o.ID, []string{"an"}, 10); err != nil || len(hits) != 1 {
+		t.Fatalf("SearchProjects after delete = %+v, %v", hits, err)
+	}
+	other, _ := newOrganisation(t, s)
+	projects, err := s.Projects.ListProjectsByID([]int{anitha.ID, anand.ID}, other.ID)
+	if err != nil || len(projects) != 0 {
+		t.Fatalf("ListProjectsByID of another organisation = %+v, %v", projects, err)
+	}
+	projects, err = s.Projects.ListProjectsByID([]int{anand.ID}, o.ID)
+	if err != nil || len(projects) != 0 {
+		t.Fatalf("ListProjectsByID of a deleted project = %+v, %v", projects, err)
+	}
+	projects, err = s.Projects.ListProjectsByID([]int{anitha.ID, anand.ID}, o.ID)
+	if err != nil || len(projects) != 1 || projects[0].ID != anitha.ID || projects[0].HTML != "" || projects[0].RawData == "" {
+		t.Fatalf("ListProjectsByID = %+v, %v", projects, err)
+	}
+	if err := s.Search.ReplaceProjectTerms(anitha.ID, o.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if hits, err := s.Search.SearchProjects(o.ID, []string{"an"}, 10); err != nil || len(hits) != 0 {
+		t.Fatalf("SearchProjects after clearing terms = %+v, %v", hits, err)
+	}
+}
+
+func TestCustomers(t *testing.T) {
+	s := openStore(t)
+	o, _ := newOrganisation(t, s)
+	w := newWorker(t, s, o.ID)
+
+	c := &models.Customer{OrganisationID: o.ID, Name: "Anitha", Phone: "98765 43210", NormalizedPhone: "9876543210", Address: "Adyar"}
+	if err := s.Customers.SaveCustomer(c); err != nil {
+		t.Fatal(err)
+	}
+	again := &models.Customer{OrganisationID: o.ID, Name: "Anitha R", Phone: "+91 98765 43210", NormalizedPhone: "9876543210", Address: "Adyar"}
+	if err := s.Customers.SaveCustomer(again); err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != c.ID || again.Name != "Anitha R" {
+		t.Fatalf("SaveCustomer of a known phone = %+v, want id %d", again, c.ID)
+	}
+	dup := &models.Customer{OrganisationID: o.ID, Name: "Anitha", Phone: "044 2345 6789", NormalizedPhone: "04423456789"}
+	if err := s.Customers.SaveCustomer(dup); err != nil {
+		t.Fatal(err)
+	}
+
+	p := newProject(t, s, w, "Anitha", "044 2345 6789")
+	if err := s.Customers.SetProjectCustomer(p.ID, &dup.ID); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "MergeCustomers in another organisation", s.Customers.MergeCustomers(o.ID+1, c.ID, dup.ID), store.ErrNotFound)
+	if err := s.Customers.MergeCustomers(o.ID, c.ID, dup.ID); err != nil {
+		t.Fatal(err)
+	}
+	wantErr(t, "MergeCustomers twice", s.Customers.MergeCustomers(o.ID, c.ID, dup.ID), store.ErrNotFound)
+	_, err := s.Customers.GetCustomer(dup.ID, o.ID)
+	wantErr(t, "GetCustomer of a merged customer", err, store.ErrNotFound)
+	if got, err := s.Customers.GetCustomer(c.ID, o.ID); err != nil || got.Name != "Anitha R" {
+		t.Fatalf("GetCustomer = %+v, %v", got, err)
+	}
+	projects, counts, err := s.Projects.ListProjectsByOrganisation(o.ID, store.ProjectQuery{CustomerID: &c.ID, Limit: 10})
+	if err != nil || counts.Total != 1 || projects[0].ID != p.ID {
+		t.Fatalf("ListProjectsByOrganisation by customer = %+v, %+v, %v", projects, counts, err)
+	}
+
+	// The merged customer's phone now finds the customer it was merged into
+	byMergedPhone := &models.Customer{OrganisationID: o.ID, Name: "Someone else", Phone: "044 2345 6789", NormalizedPhone: "04423456789"}
+	if err := s.Customers.SaveCustomer(byMergedPhone); err != nil {
+		t.Fatal(err)
+	}
+	if byMergedPhone.ID != c.ID || byMergedPhone.Name != "Anitha R" {
+		t.Fatalf("SaveCustomer of a merged phone = %+v, want id %d", byMergedPhone, c.ID)
+	}
+
+	if err := s.Customers.SetProjectCustomer(p.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Projects.GetProject(p.ID, o.ID); err != nil || got.CustomerID != nil {
+		t.Fatalf("GetProject after unlinking = %+v, %v", got, err)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	s := openStore(t)
+	o, _ := newOrganisation(t, s)
+	now := time.Now()
+
+	b := &models.Brand{Name: "Acme Fabrics", OrganisationID: o.ID, IsActive: true, CreatedAt: now, UpdatedAt: now}
+	if err := s.Catalog.CreateBrand(b); err != nil {
+		t.Fatal(err)
+	}
+	f := &models.Folder{Name: "Linen", BrandID: b.ID, OrganisationID: o.ID, IsActive: true, CreatedAt: now, UpdatedAt: now}
+	if err := s.Catalog.CreateFolder(f); err != nil {
+		t.Fatal(err)
+	}
+	cl := &models.Cloth{Name: "Ivory", Rate: 450.5, FolderID: f.ID, BrandID: b.ID, OrganisationID: o.ID, IsActive: true, CreatedAt: now, UpdatedAt: now}
+	if err := s.Catalog.CreateCloth(cl); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := s.Catalog.ActiveBrandExists(b.ID, o.ID); err != nil || !ok {
+		t.Fatalf("ActiveBrandExists = %v, %v", ok, err)
+	}
+	if ok, err := s.Catalog.BrandHasFolders(b.ID); err != nil || !ok {
+		t.Fatalf("BrandHasFolders = %v, %v", ok, err)
+	}
+	if ok, err := s.Catalog.FolderInBrand(f.ID, b.ID); err != nil || !ok {
+		t.Fatalf("FolderInBrand = %v, %v", ok, err)
+	}
+	if ok, err := s.Catalog.FolderHasCloths(f.ID); err != nil || !ok {
+		t.Fatalf("FolderHasCloths = %v, %v", ok, err)
+	}
+
+	got, err := s.Catalog.GetCloth(cl.ID, o.ID)
+	if err != nil || got.FolderName != "Linen" || got.BrandName != "Acme Fabrics" || got.Rate != 450.5 {
+		t.Fatalf("GetCloth = %+v, %v", got, err)
+	}
+	rate, inactive := 500.0, false
+	if err := s.Catalog.UpdateCloth(cl.ID, o.ID, store.ClothUpdate{Rate: &rate, IsActive: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	active := true
+	cloths, err := s.Catalog.ListCloths(o.ID, store.ClothFilter{FolderID: &f.ID, Active: &active})
+	if err != nil || len(cloths) != 0 {
+		t.Fatalf("ListCloths active = %+v, %v", cloths, err)
+	}
+	if err := s.Catalog.DeleteCloth(cl.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	name := "Cotton"
+	if err := s.Catalog.UpdateFolder(f.ID, o.ID, store.FolderUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	folders, err := s.Catalog.ListFolders(o.ID, store.FolderFilter{BrandID: &b.ID})
+	if err != nil || len(folders) != 1 || folders[0].Name != "Cotton" || folders[0].BrandName != "Acme Fabrics" {
+		t.Fatalf("ListFolders = %+v, %v", folders, err)
+	}
+	if ok, err := s.Catalog.ActiveFolderExists(f.ID, o.ID); err != nil || !ok {
+		t.Fatalf("ActiveFolderExists = %v, %v", ok, err)
+	}
+	if _, err := s.Catalog.GetFolder(f.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Catalog.DeleteFolder(f.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Catalog.UpdateBrand(b.ID, o.ID, store.BrandUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	brands, err := s.Catalog.ListBrands(o.ID, store.BrandFilter{})
+	if err != nil || len(brands) != 1 || brands[0].Name != "Cotton" {
+		t.Fatalf("ListBrands = %+v, %v", brands, err)
+	}
+	if _, err := s.Catalog.GetBrand(b.ID, o.ID+1); err != store.ErrNotFound {
+		t.Fatalf("GetBrand in another organisation: %v", err)
+	}
+	if err := s.Catalog.DeleteBrand(b.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,31 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+// Store implements the repositories on a SQL database of any Dialect
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// New wraps an open database connection in the repository interfaces
+func New(db *sql.DB, d Dialect) *store.Store {
+	s := &Store{db: db, dialect: d}
+	return &store.Store{Projects: s, Workers: s, Admins: s, Catalog: NewCatalog(db, d), Templates: s, Measurements: s, Revisions: s, Audit: s, Tokens: s, Logins: s, Search: s, Customers: s}
+}
+
+// execer runs statements on the database or inside a transaction
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// lockTable runs the dialect's table lock in tx
+func (s *Store) lockTable(tx *sql.Tx, table string) error {
+	_, err := tx.Exec(s.dialect.LockTable(table))
+	return err
+}
@@ -0,0 +1,46 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/Vanaraj10/interior-backend/models"
+)
+
+func (s *Store) GetQuotationTemplate(orgID int) (*models.QuotationTemplate, error) {
+	t := models.QuotationTemplate{OrganisationID: orgID}
+	var notes sql.NullString
+	q := s.dialect.Query(`SELECT company_name, logo_url, notes, stitching_template, updated_at FROM quotation_templates WHERE organisation_id = ?`, orgID)
+	err := s.db.QueryRow(q.SQL(), q.Args()...).Scan(&t.CompanyName, &t.LogoURL, &notes, &t.StitchingTemplate, &t.UpdatedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if notes.Valid {
+		t.Notes = []string{}
+		if notes.String != "" {
+			t.Notes = strings.Split(notes.String, "\n")
+		}
+	}
+	return &t, nil
+}
+
+func (s *Store) SaveQuotationTemplate(t *models.QuotationTemplate) error {
+	// Notes are stored one per line; NULL keeps the default notes
+	var notes sql.NullString
+	if t.Notes != nil {
+		notes = sql.NullString{String: strings.Join(t.Notes, "\n"), Valid: true}
+	}
+	q := s.dialect.Upsert("quotation_templates", []string{"organisation_id"},
+		[]string{"organisation_id", "company_name", "logo_url", "notes", "stitching_template", "updated_at"},
+		t.OrganisationID, t.CompanyName, t.LogoURL, notes, t.StitchingTemplate, Now)
+	if _, err := s.db.Exec(q.SQL(), q.Args()...); err != nil {
+		return err
+	}
+	q = s.dialect.Query(`SELECT updated_at FROM quotation_templates WHERE organisation_id = ?`, t.OrganisationID)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&t.UpdatedAt)
+}
+
+func (s *Store) DeleteQuotationTemplate(orgID int) error {
+	q := s.dialect.Query(`DELETE FROM quotation_templates WHERE organisation_id = ?`, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
@@ -0,0 +1,92 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+func (s *Store) insertRefreshToken(t *models.RefreshToken) *Query {
+	return s.dialect.InsertReturning("refresh_tokens",
+		[]string{"token_hash", "session_id", "role", "user_id", "organisation_id", "expires_at", "created_at"}, []string{"id", "created_at"},
+		t.TokenHash, t.SessionID, t.Role, t.UserID, t.OrganisationID, t.ExpiresAt, Now)
+}
+
+func (s *Store) CreateRefreshToken(t *models.RefreshToken) error {
+	q := s.insertRefreshToken(t)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&t.ID, &t.CreatedAt)
+}
+
+func (s *Store) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	var t models.RefreshToken
+	q := s.dialect.Query(`SELECT id, token_hash, session_id, role, user_id, organisation_id, expires_at, created_at, revoked_at FROM refresh_tokens WHERE token_hash = ?`, tokenHash)
+	err := s.db.QueryRow(q.SQL(), q.Args()...).
+		Scan(&t.ID, &t.TokenHash, &t.SessionID, &t.Role, &t.UserID, &t.OrganisationID, &t.ExpiresAt, &t.CreatedAt, &t.RevokedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &t, nil
+}
+
+func (s *Store) RotateRefreshToken(oldID int, next *models.RefreshToken) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Only one of two concurrent refreshes with the same token can win
+	q := s.dialect.Query(`UPDATE refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, Now, oldID)
+	err = affected(tx.Exec(q.SQL(), q.Args()...))
+	if err == store.ErrNotFound {
+		return store.ErrConflict
+	} else if err != nil {
+		return err
+	}
+	q = s.insertRefreshToken(next)
+	if err := tx.QueryRow(q.SQL(), q.Args()...).Scan(&next.ID, &next.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) SessionActive(sessionID string) (bool, error) {
+	q := s.dialect.Query(`SELECT COUNT(*) FROM refresh_tokens WHERE session_id = ? AND revoked_at IS NULL AND expires_at > ?`, sessionID, time.Now())
+	var count int
+	err := s.db.QueryRow(q.SQL(), q.Args()...).Scan(&count)
+	return count > 0, err
+}
+
+func (s *Store) RevokeSession(sessionID string) error {
+	q := s.dialect.Query(`UPDATE refresh_tokens SET revoked_at = ? WHERE session_id = ? AND revoked_at IS NULL`, Now, sessionID)
+	_, err := s.db.Exec(q.SQL(), q.Args()...)
+	return err
+}
+
+func (s *Store) RevokeUserSessions(role string, userID int) (int, error) {
+	return s.revokeSessions(s.dialect.Query(`role = ? AND user_id = ?`, role, userID))
+}
+
+func (s *Store) RevokeOtherSessions(role string, userID int, keepSessionID string) (int, error) {
+	return s.revokeSessions(s.dialect.Query(`role = ? AND user_id = ? AND session_id <> ?`, role, userID, keepSessionID))
+}
+
+// revokeSessions revokes the unrevoked tokens matching where and returns the
+// number of sessions that were still active
+func (s *Store) revokeSessions(where *Query) (int, error) {
+	q := s.dialect.Query(`SELECT COUNT(DISTINCT session_id) FROM refresh_tokens WHERE `).Append(where).
+		Write(` AND revoked_at IS NULL AND expires_at > ?`, time.Now())
+	var sessions int
+	if err := s.db.QueryRow(q.SQL(), q.Args()...).Scan(&sessions); err != nil {
+		return 0, err
+	}
+	q = s.dialect.Query(`UPDATE refresh_tokens SET revoked_at = ? WHERE `, Now).Append(where).Write(` AND revoked_at IS NULL`)
+	_, err := s.db.Exec(q.SQL(), q.Args()...)
+	return sessions, err
+}
+
+func (s *Store) PurgeRefreshTokens(expiredBefore time.Time) (int, error) {
+	q := s.dialect.Query(`DELETE FROM refresh_tokens WHERE expires_at < ?`, expiredBefore)
+	return rowsAffected(s.db.Exec(q.SQL(), q.Args()...))
+}
@@ -0,0 +1,121 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/store"
+)
+
+const workerColumns = `id, username, password_hash, organisation_id, name, phone, is_active, created_at, deleted_at`
+
+func scanWorker(row interface{ Scan(...interface{}) error }, w *models.Worker) error {
+	return row.Scan(&w.ID, &w.Username, &w.PasswordHash, &w.OrganisationID, &w.Name, &w.Phone, &w.IsActive, &w.CreatedAt, &w.DeletedAt)
+}
+
+func (s *Store) CreateWorker(w *models.Worker) error {
+	q := s.dialect.InsertReturning("workers",
+		[]string{"username", "password_hash", "organisation_id", "name", "phone", "is_active", "created_at"}, []string{"id", "created_at"},
+		w.Username, w.PasswordHash, w.OrganisationID, w.Name, w.Phone, w.IsActive, Now)
+	return s.db.QueryRow(q.SQL(), q.Args()...).Scan(&w.ID, &w.CreatedAt)
+}
+
+func (s *Store) GetWorker(id, orgID int) (*models.Worker, error) {
+	return s.getWorker(s.dialect.Query(`SELECT `+workerColumns+` FROM workers WHERE id = ? AND organisation_id = ? AND deleted_at IS NULL`, id, orgID))
+}
+
+func (s *Store) GetWorkerByUsername(username string) (*models.Worker, error) {
+	return s.getWorker(s.dialect.Query(`SELECT `+workerColumns+` FROM workers WHERE username = ? AND deleted_at IS NULL`, username))
+}
+
+func (s *Store) getWorker(q *Query) (*models.Worker, error) {
+	var w models.Worker
+	if err := scanWorker(s.db.QueryRow(q.SQL(), q.Args()...), &w); err != nil {
+		return nil, notFound(err)
+	}
+	return &w, nil
+}
+
+func (s *Store) WorkerUsernameExists(username string) (bool, error) {
+	q := s.dialect.Query(`SELECT COUNT(*) FROM workers WHERE username = ?`, username)
+	var count int
+	err := s.db.QueryRow(q.SQL(), q.Args()...).Scan(&count)
+	return count > 0, err
+}
+
+func (s *Store) ListWorkers(orgID int, filter store.WorkerFilter) ([]models.Worker, error) {
+	q := s.dialect.Query(`SELECT `+workerColumns+` FROM workers WHERE organisation_id = ?`, orgID)
+	if filter.Deleted {
+		q.Write(` AND deleted_at IS NOT NULL`)
+	} else {
+		q.Write(` AND deleted_at IS NULL`)
+	}
+	rows, err := s.db.Query(q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var workers []models.Worker
+	for rows.Next() {
+		var w models.Worker
+		if err := scanWorker(rows, &w); err != nil {
+			return nil, err
+		}
+		workers = append(workers, w)
+	}
+	return workers, rows.Err()
+}
+
+func (s *Store) UpdateWorker(id, orgID int, update store.WorkerUpdate) error {
+	q := s.dialect.Query(`UPDATE workers SET`)
+	sep := " "
+	set := func(column string, v interface{}) {
+		q.Write(sep+column+` = ?`, v)
+		sep = ", "
+	}
+	if update.Username != nil {
+		taken := s.dialect.Query(`SELECT COUNT(*) FROM workers WHERE username = ? AND id <> ?`, *update.Username, id)
+		var count int
+		if err := s.db.QueryRow(taken.SQL(), taken.Args()...).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return store.ErrConflict
+		}
+		set("username", *update.Username)
+	}
+	if update.Name != nil {
+		set("name", *update.Name)
+	}
+	if update.Phone != nil {
+		set("phone", *update.Phone)
+	}
+	if update.IsActive != nil {
+		set("is_active", *update.IsActive)
+	}
+	if sep == " " {
+		return nil
+	}
+	q.Write(` WHERE id = ? AND organisation_id = ? AND deleted_at IS NULL`, id, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) UpdateWorkerPassword(id, orgID int, passwordHash string) error {
+	q := s.dialect.Query(`UPDATE workers SET password_hash = ? WHERE id = ? AND organisation_id = ? AND deleted_at IS NULL`, passwordHash, id, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) DeleteWorker(id, orgID int) error {
+	q := s.dialect.Query(`UPDATE workers SET deleted_at = ? WHERE id = ? AND organisation_id = ? AND deleted_at IS NULL`, Now, id, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) RestoreWorker(id, orgID int) error {
+	q := s.dialect.Query(`UPDATE workers SET deleted_at = NULL WHERE id = ? AND organisation_id = ? AND deleted_at IS NOT NULL`, id, orgID)
+	return affected(s.db.Exec(q.SQL(), q.Args()...))
+}
+
+func (s *Store) PurgeWorkers(deletedBefore time.Time) (int, error) {
+	q := s.dialect.Query(`DELETE FROM workers WHERE deleted_at < ? AND NOT EXISTS (SELECT 1 FROM projects p WHERE p.worker_id = workers.id)`, deletedBefore)
+	return rowsAffected(s.db.Exec(q.SQL(), q.Args()...))
+}
@@ -0,0 +1,376 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+)
+
+var (
+	// ErrNotFound is returned when a row does not exist or belongs to another organisation
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a unique value (e.g. a username) is already taken
+	ErrConflict = errors.New("already exists")
+)
+
+// ProjectStore persists worker-submitted projects. Deleted projects are kept
+// until they are purged, but are not found or changed by the other methods
+// unless stated.
+type ProjectStore interface {
+	CreateProject(p *models.Project) error
+	// UpdateProject overwrites a project owned by p.WorkerID and p.OrganisationID
+	UpdateProject(p *models.Project) error
+	// SaveProject creates p, or updates it as UpdateProject does when p.ID
+	// is set, and appends r as its next revision, all or nothing. It sets
+	// r.ProjectID along with what CreateRevision sets.
+	SaveProject(p *models.Project, r *models.ProjectRevision) error
+	GetProject(id, orgID int) (*models.Project, error)
+	// ListProjectsByOrganisation returns one page of an organisation's
+	// projects, without their HTML, along with the counts of every matching
+	// project
+	ListProjectsByOrganisation(orgID int, q ProjectQuery) ([]models.Project, ProjectCounts, error)
+	// ListProjectsByID returns those of the given projects that belong to the
+	// organisation and are not deleted, without their HTML, in no set order
+	ListProjectsByID(ids []int, orgID int) ([]models.Project, error)
+	// GetWorkerProject returns a project submitted by the given worker
+	GetWorkerProject(id, workerID int) (*models.Project, error)
+	// ListProjectsByWorker returns one page of a worker's projects ordered by
+	// updated_at, id, along with the total number of matching projects. With
+	// q.Since set, deleted projects are included so clients can drop them.
+	ListProjectsByWorker(workerID int, q WorkerProjectQuery) ([]models.Project, int, error)
+	// ListProjectsAfter returns up to limit projects of every organisation with an id
+	// above afterID, ordered by id, for maintenance jobs such as backfills.
+	// Deleted projects are included.
+	ListProjectsAfter(afterID, limit int) ([]models.Project, error)
+	// SetProjectStatus moves project t.ProjectID from t.FromStatus to
+	// t.ToStatus and records t. It returns ErrConflict if the project is no
+	// longer at t.FromStatus. Reaching a completed status also sets is_completed.
+	SetProjectStatus(t *models.StatusTransition) error
+	// ListStatusTransitions returns a project's status history, oldest first
+	ListStatusTransitions(projectID int) ([]models.StatusTransition, error)
+	// DeleteProject marks a project deleted; RestoreProject undoes it
+	DeleteProject(id, orgID int) error
+	RestoreProject(id, orgID int) error
+	// ReassignProjects moves every project of one worker, deleted ones
+	// included, to another and returns how many were moved
+	ReassignProjects(fromWorkerID, toWorkerID, orgID int) (int, error)
+	// PurgeProjects permanently removes projects deleted before the given time
+	PurgeProjects(deletedBefore time.Time) (int, error)
+}
+
+// WorkerStore persists worker accounts. Deleted workers cannot log in and are
+// not found by GetWorker, but keep their username until they are purged.
+// Inactive workers are found as usual; it is up to callers to refuse them.
+type WorkerStore interface {
+	CreateWorker(w *models.Worker) error
+	GetWorker(id, orgID int) (*models.Worker, error)
+	GetWorkerByUsername(username string) (*models.Worker, error)
+	WorkerUsernameExists(username string) (bool, error)
+	ListWorkers(orgID int, filter WorkerFilter) ([]models.Worker, error)
+	// UpdateWorker returns ErrConflict if the new username is already taken
+	UpdateWorker(id, orgID int, update WorkerUpdate) error
+	UpdateWorkerPassword(id, orgID int, passwordHash string) error
+	// DeleteWorker marks a worker deleted; RestoreWorker undoes it
+	DeleteWorker(id, orgID int) error
+	RestoreWorker(id, orgID int) error
+	// PurgeWorkers permanently removes workers deleted before the given time
+	// that no longer have any projects
+	PurgeWorkers(deletedBefore time.Time) (int, error)
+}
+
+// AdminStore persists organisations, their admin accounts and signup invites
+type AdminStore interface {
+	// CreateOrganisation creates an organisation together with its first
+	// admin, setting owner.OrganisationID. It returns ErrConflict if the
+	// username is already taken.
+	CreateOrganisation(o *models.Organisation, owner *models.Admin) error
+	// CreateFirstOrganisation is CreateOrganisation for first-run setup; it
+	// returns ErrConflict if any admin exists already
+	CreateFirstOrganisation(o *models.Organisation, owner *models.Admin) error
+	GetOrganisation(id int) (*models.Organisation, error)
+	UpdateOrganisation(id int, name string) error
+	// CreateAdmin returns ErrConflict if the username is already taken
+	CreateAdmin(a *models.Admin) error
+	GetAdmin(id int) (*models.Admin, error)
+	GetAdminByUsername(username string) (*models.Admin, error)
+	// ListAdmins returns an organisation's admins ordered by id
+	ListAdmins(orgID int) ([]models.Admin, error)
+	UpdateAdminPassword(id int, passwordHash string) error
+	UpdateAdminRole(id, orgID int, role string) error
+	DeleteAdmin(id, orgID int) error
+	CountAdmins() (int, error)
+	CreateInvite(inv *models.Invite) error
+	// RedeemInvite uses up an unexpired invite to create an organisation and
+	// its owner, as CreateOrganisation does. It returns ErrNotFound if no
+	// unused invite has the code hash, and ErrConflict if the username is taken.
+	RedeemInvite(codeHash string, o *models.Organisation, owner *models.Admin) error
+}
+
+// CatalogStore persists brands, folders and cloths
+type CatalogStore interface {
+	CreateBrand(b *models.Brand) error
+	ListBrands(orgID int, filter BrandFilter) ([]models.Brand, error)
+	GetBrand(id, orgID int) (*models.Brand, error)
+	UpdateBrand(id, orgID int, update BrandUpdate) error
+	DeleteBrand(id, orgID int) error
+	ActiveBrandExists(id, orgID int) (bool, error)
+	BrandHasFolders(id int) (bool, error)
+
+	CreateFolder(f *models.Folder) error
+	ListFolders(orgID int, filter FolderFilter) ([]models.FolderWithBrand, error)
+	GetFolder(id, orgID int) (*models.FolderWithBrand, error)
+	UpdateFolder(id, orgID int, update FolderUpdate) error
+	DeleteFolder(id, orgID int) error
+	ActiveFolderExists(id, orgID int) (bool, error)
+	FolderInBrand(folderID, brandID int) (bool, error)
+	FolderHasCloths(id int) (bool, error)
+
+	CreateCloth(cl *models.Cloth) error
+	ListCloths(orgID int, filter ClothFilter) ([]models.ClothWithDetails, error)
+	GetCloth(id, orgID int) (*models.ClothWithDetails, error)
+	UpdateCloth(id, orgID int, update ClothUpdate) error
+	DeleteCloth(id, orgID int) error
+}
+
+// TemplateStore persists each organisation's quotation branding and custom templates
+type TemplateStore interface {
+	// GetQuotationTemplate returns ErrNotFound when the organisation uses the defaults
+	GetQuotationTemplate(orgID int) (*models.QuotationTemplate, error)
+	// SaveQuotationTemplate creates or replaces the organisation's settings
+	SaveQuotationTemplate(t *models.QuotationTemplate) error
+	DeleteQuotationTemplate(orgID int) error
+}
+
+// MeasurementStore persists the rooms and measurements normalized from each
+// project's rawData, so they can be queried across projects
+type MeasurementStore interface {
+	// ReplaceProjectMeasurements replaces all rooms and measurements of a
+	// project; measurements are linked to rooms by RoomKey
+	ReplaceProjectMeasurements(projectID int, rooms []models.Room, measurements []models.Measurement) error
+	ListProjectMeasurements(projectID int) ([]models.Room, []models.Measurement, error)
+}
+
+// CustomerStore keeps an organisation's customers, one per normalized phone
+// number, and which customer each project is for
+type CustomerStore interface {
+	// SaveCustomer finds the customer of c.OrganisationID with
+	// c.NormalizedPhone, or creates it, updates its name, phone and address
+	// to c's and fills in c. A customer merged into another is left as it is
+	// and c is set to the other customer.
+	SaveCustomer(c *models.Customer) error
+	// GetCustomer returns a customer that has not been merged into another
+	GetCustomer(id, orgID int) (*models.Customer, error)
+	// SetProjectCustomer links a project to a customer, or unlinks it with nil
+	SetProjectCustomer(projectID int, customerID *int) error
+	// MergeCustomers moves the projects of customer fromID, deleted ones
+	// included, to customer intoID and marks fromID, and any customers
+	// merged into it, merged into intoID. It returns ErrNotFound unless both
+	// are unmerged customers of orgID.
+	MergeCustomers(orgID, intoID, fromID int) error
+}
+
+// SearchStore keeps the search index: the terms of each project's fields
+type SearchStore interface {
+	// ReplaceProjectTerms replaces the indexed terms of a project
+	ReplaceProjectTerms(projectID, orgID int, terms []models.SearchTerm) error
+	// SearchProjects returns up to limit of an organisation's projects, not
+	// deleted, that have a term starting with each query term. A project
+	// scores the weight of its best match for each query term, doubled when
+	// the term is equal; the best scores come first, then the newest projects.
+	SearchProjects(orgID int, terms []string, limit int) ([]SearchHit, error)
+}
+
+// SearchHit is a project found by SearchProjects
+type SearchHit struct {
+	ProjectID int
+	Score     int
+}
+
+// RevisionStore keeps an append-only history of every saved version of a project
+type RevisionStore interface {
+	// CreateRevision appends r as the next revision of r.ProjectID and sets
+	// r.ID, r.Revision and r.CreatedAt
+	CreateRevision(r *models.ProjectRevision) error
+	// ListRevisions returns a project's revisions, oldest first
+	ListRevisions(projectID int) ([]models.ProjectRevision, error)
+	GetRevision(projectID, revision int) (*models.ProjectRevision, error)
+}
+
+// AuditStore records mutating requests so they can be reviewed later
+type AuditStore interface {
+	RecordAudit(e *models.AuditEntry) error
+	// ListAudit returns one page of an organisation's audit entries, newest first,
+	// along with the total number of matching entries
+	ListAudit(orgID int, q AuditQuery) ([]models.AuditEntry, int, error)
+}
+
+// TokenStore persists refresh tokens and the login sessions they belong to
+type TokenStore interface {
+	CreateRefreshToken(t *models.RefreshToken) error
+	// GetRefreshToken looks a token up by its hash, revoked or not
+	GetRefreshToken(tokenHash string) (*models.RefreshToken, error)
+	// RotateRefreshToken revokes the token with id oldID and stores next in
+	// its place. It returns ErrConflict if the old token was already revoked.
+	RotateRefreshToken(oldID int, next *models.RefreshToken) error
+	// SessionActive reports whether a session has an unrevoked, unexpired token
+	SessionActive(sessionID string) (bool, error)
+	RevokeSession(sessionID string) error
+	// RevokeUserSessions ends every session of a user and returns how many were active
+	RevokeUserSessions(role string, userID int) (int, error)
+	// RevokeOtherSessions is RevokeUserSessions keeping the session keepSessionID
+	RevokeOtherSessions(role string, userID int, keepSessionID string) (int, error)
+	// PurgeRefreshTokens removes tokens that expired before the given time
+	PurgeRefreshTokens(expiredBefore time.Time) (int, error)
+}
+
+// LoginStore records rejected login attempts for admins to review
+type LoginStore interface {
+	RecordFailedLogin(f *models.FailedLogin) error
+	// ListFailedLogins returns one page of the failed logins on an organisation's
+	// accounts, newest first, along with the total number of matching entries
+	ListFailedLogins(orgID int, q FailedLoginQuery) ([]models.FailedLogin, int, error)
+}
+
+// Store groups every repository the handlers depend on
+type Store struct {
+	Projects     ProjectStore
+	Workers      WorkerStore
+	Admins       AdminStore
+	Catalog      CatalogStore
+	Templates    TemplateStore
+	Measurements MeasurementStore
+	Revisions    RevisionStore
+	Audit        AuditStore
+	Tokens       TokenStore
+	Logins       LoginStore
+	Search       SearchStore
+	Customers    CustomerStore
+}
+
+// WorkerProjectQuery selects a page of a worker's projects
+type WorkerProjectQuery struct {
+	// Since, if set, keeps only projects updated at or after this time
+	Since  *time.Time
+	Limit  int
+	Offset int
+}
+
+// AuditQuery selects a page of audit entries; empty and nil fields are not applied
+type AuditQuery struct {
+	EntityType string
+	EntityID   *int
+	ActorRole  string
+	ActorID    *int
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// FailedLoginQuery selects a page of failed logins; empty fields are not applied
+type FailedLoginQuery struct {
+	Role     string
+	Username string
+	Limit    int
+	Offset   int
+}
+
+// ProjectQuery selects a page of an organisation's projects; nil and empty
+// fields are not applied. Deleted lists only deleted projects instead of the
+// others.
+type ProjectQuery struct {
+	Status    *string
+	Completed *bool
+	WorkerID  *int
+	// CustomerID keeps the projects of one customer
+	CustomerID *int
+	// InteriorType keeps projects with a measurement of that type
+	InteriorType string
+	// Phone keeps projects whose phone contains it
+	Phone string
+	// From and To bound the creation time
+	From    *time.Time
+	To      *time.Time
+	Deleted bool
+	// Sort defaults to SortCreatedAt; ties are broken by id
+	Sort   ProjectSort
+	Desc   bool
+	Limit  int
+	Offset int
+}
+
+// ProjectSort is a column projects can be ordered by
+type ProjectSort string
+
+const (
+	SortCreatedAt  ProjectSort = "created_at"
+	SortUpdatedAt  ProjectSort = "updated_at"
+	SortClientName ProjectSort = "client_name"
+	SortStatus     ProjectSort = "status"
+)
+
+// ProjectCounts counts the projects matching a ProjectQuery, on every page
+type ProjectCounts struct {
+	Total     int
+	Completed int
+}
+
+// WorkerFilter narrows ListWorkers. Deleted lists only deleted workers
+// instead of the others.
+type WorkerFilter struct {
+	Deleted bool
+}
+
+// BrandFilter narrows ListBrands; nil fields are not applied
+type BrandFilter struct {
+	Active *bool
+}
+
+// FolderFilter narrows ListFolders; nil fields are not applied
+type FolderFilter struct {
+	BrandID *int
+	Active  *bool
+}
+
+// ClothFilter narrows ListCloths; nil fields are not applied
+type ClothFilter struct {
+	BrandID  *int
+	FolderID *int
+	Active   *bool
+}
+
+// WorkerUpdate holds the worker fields to change; nil fields are left as they are
+type WorkerUpdate struct {
+	Username *string
+	Name     *string
+	Phone    *string
+	IsActive *bool
+}
+
+// BrandUpdate holds the brand fields to change; nil fields are left as they are
+type BrandUpdate struct {
+	Name        *string
+	Description *string
+	LogoURL     *string
+	IsActive    *bool
+}
+
+// FolderUpdate holds the folder fields to change; nil fields are left as they are
+type FolderUpdate struct {
+	Name        *string
+	Description *string
+	BrandID     *int
+	IsActive    *bool
+}
+
+// ClothUpdate holds the cloth fields to change; nil fields are left as they are
+type ClothUpdate struct {
+	Name        *string
+	Rate        *float64
+	Description *string
+	ImageURL    *string
+	FolderID    *int
+	BrandID     *int
+	IsActive    *bool
+}
@@ -0,0 +1,112 @@
+package templates
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/Vanaraj10/interior-backend/models"
+	"github.com/Vanaraj10/interior-backend/pricing"
+)
+
+//go:embed stitching.html
+var defaultStitching string
+
+// MaxTemplateSize limits the size of an uploaded template
+const MaxTemplateSize = 256 << 10
+
+// DefaultNotes are printed on stitching sheets unless the admin sets their own
+var DefaultNotes = []string{
+	"All measurements are in inches",
+	"Parts calculation is based on width optimization for standard cloth width",
+	"Verify lining requirements before cutting",
+	"Follow stitching model specifications exactly",
+	"Contact client for any clarifications before proceeding",
+}
+
+// Branding is the shop-specific part of a stitching sheet
+type Branding struct {
+	CompanyName string
+	LogoURL     string
+	Notes       []string
+}
+
+// StitchingSheet is the data a stitching template is executed with
+type StitchingSheet struct {
+	Branding Branding
+	Project  models.Project
+	Rooms    []pricing.RoomBreakdown
+}
+
+var funcs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+	"num": func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) },
+}
+
+// DefaultStitchingTemplate returns the built-in stitching sheet template source
+func DefaultStitchingTemplate() string {
+	return defaultStitching
+}
+
+// BrandingFor applies an admin's saved settings over the defaults; t may be nil
+func BrandingFor(t *models.QuotationTemplate) Branding {
+	b := Branding{Notes: DefaultNotes}
+	if t == nil {
+		return b
+	}
+	b.CompanyName = t.CompanyName
+	b.LogoURL = t.LogoURL
+	if t.Notes != nil {
+		b.Notes = t.Notes
+	}
+	return b
+}
+
+// RenderStitching executes the given template source, or the built-in one when
+// source is empty. Values are escaped by html/template.
+func RenderStitching(source string, sheet StitchingSheet) (string, error) {
+	if source == "" {
+		source = defaultStitching
+	}
+	tmpl, err := template.New("stitching").Funcs(funcs).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, sheet); err != nil {
+		return "", fmt.Errorf("rendering template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Validate checks that a custom template parses and renders the sample sheet
+func Validate(source string, branding Branding) error {
+	if len(source) > MaxTemplateSize {
+		return fmt.Errorf("template is larger than %d KB", MaxTemplateSize>>10)
+	}
+	_, err := RenderStitching(source, SampleSheet(branding))
+	return err
+}
+
+// SampleSheet is the example project used to validate and preview templates
+func SampleSheet(branding Branding) StitchingSheet {
+	q := pricing.Quote([]pricing.CurtainInput{
+		{RoomID: "1", RoomName: "Living Room", RoomLabel: "Main Window", StitchingModel: "Eyelet", Opening: "Single Open", Width: 72, Height: 84, HasLining: true, LiningModel: "Blackout Lining"},
+		{RoomID: "1", RoomName: "Living Room", RoomLabel: "Side Window", StitchingModel: "Pleated", Opening: "No open", Width: 36, Height: 60},
+		{RoomID: "2", RoomName: "Bedroom", RoomLabel: "Balcony Door", StitchingModel: "Ripple Curtain", Width: 110, Height: 96},
+	})
+	return StitchingSheet{
+		Branding: branding,
+		Project: models.Project{
+			ID:         1001,
+			ClientName: "Sample Client",
+			Phone:      "9876543210",
+			Address:    "12 Sample Street, Chennai",
+			CreatedAt:  time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+		},
+		Rooms: q.Rooms,
+	}
+}